@@ -0,0 +1,139 @@
+package esimgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// activationCodePrefix starts every LPA activation code (format version 1)
+const activationCodePrefix = "LPA:1$"
+
+// ErrInvalidActivationCode is returned for malformed LPA activation codes
+var ErrInvalidActivationCode = errors.New("esimgo: invalid activation code")
+
+// ActivationCode builds the LPA activation code a device scans to install
+// an eSIM profile, in the form "LPA:1$<SM-DP+ address>$<matching ID>"
+func ActivationCode(smdpAddress, matchingID string) (string, error) {
+	smdpAddress = strings.TrimSpace(smdpAddress)
+	matchingID = strings.TrimSpace(matchingID)
+
+	if smdpAddress == "" {
+		return "", fmt.Errorf("%w: missing SM-DP+ address", ErrInvalidActivationCode)
+	}
+	if strings.Contains(smdpAddress, "$") || strings.Contains(matchingID, "$") {
+		return "", fmt.Errorf("%w: fields must not contain '$'", ErrInvalidActivationCode)
+	}
+
+	return activationCodePrefix + smdpAddress + "$" + matchingID, nil
+}
+
+// ParseActivationCode extracts the SM-DP+ address and matching ID from an
+// LPA activation code
+func ParseActivationCode(code string) (smdpAddress, matchingID string, err error) {
+	code = strings.TrimSpace(code)
+	if len(code) < len(activationCodePrefix) || !strings.EqualFold(code[:len(activationCodePrefix)], activationCodePrefix) {
+		return "", "", fmt.Errorf("%w: missing %q prefix", ErrInvalidActivationCode, activationCodePrefix)
+	}
+
+	// Optional trailing fields, such as the confirmation code flag, follow
+	// the matching ID
+	fields := strings.Split(code[len(activationCodePrefix):], "$")
+	if fields[0] == "" {
+		return "", "", fmt.Errorf("%w: missing SM-DP+ address", ErrInvalidActivationCode)
+	}
+	if len(fields) > 1 {
+		matchingID = fields[1]
+	}
+	return fields[0], matchingID, nil
+}
+
+// ActivationQRCode encodes an LPA activation code as a QR code, using the
+// medium error correction level recommended by GSMA SGP.22
+func ActivationQRCode(smdpAddress, matchingID string) (*QRCode, error) {
+	code, err := ActivationCode(smdpAddress, matchingID)
+	if err != nil {
+		return nil, err
+	}
+	return NewQRCode([]byte(code), QRErrorCorrectionMedium)
+}
+
+// ActivationCode returns the LPA activation code of the eSIM
+func (e *ESIM) ActivationCode() (string, error) {
+	return ActivationCode(e.SMDPAddress, e.MatchingID)
+}
+
+// QRCode returns the installation QR code of the eSIM
+func (e *ESIM) QRCode() (*QRCode, error) {
+	return ActivationQRCode(e.SMDPAddress, e.MatchingID)
+}
+
+// InstallDetails represents the details needed to install an eSIM profile
+type InstallDetails struct {
+	ICCID       ICCID  `json:"iccid"`
+	MatchingID  string `json:"matchingId"`
+	SMDPAddress string `json:"smdpAddress"`
+}
+
+// ActivationCode returns the LPA activation code of the eSIM
+func (d *InstallDetails) ActivationCode() (string, error) {
+	return ActivationCode(d.SMDPAddress, d.MatchingID)
+}
+
+// QRCode returns the installation QR code of the eSIM
+func (d *InstallDetails) QRCode() (*QRCode, error) {
+	return ActivationQRCode(d.SMDPAddress, d.MatchingID)
+}
+
+// assignmentsEndpoint returns the install details endpoint for an order
+// reference or a list of ICCIDs
+func assignmentsEndpoint(reference string, iccids []ICCID) (string, error) {
+	params := url.Values{}
+	switch {
+	case reference != "":
+		params.Set("reference", reference)
+	case len(iccids) > 0:
+		values := make([]string, len(iccids))
+		for i, iccid := range iccids {
+			values[i] = iccid.String()
+		}
+		params.Set("iccids", strings.Join(values, ","))
+	default:
+		return "", errors.New("an order reference or at least one ICCID is required")
+	}
+	return "/esims/assignments?" + params.Encode(), nil
+}
+
+// GetInstallDetails retrieves the install details of the eSIMs assigned by
+// an order. When reference is empty the given ICCIDs are looked up instead.
+func (s *ESIMService) GetInstallDetails(ctx context.Context, reference string, iccids ...ICCID) ([]InstallDetails, error) {
+	endpoint, err := assignmentsEndpoint(reference, iccids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get install details: %w", err)
+	}
+
+	var resp []InstallDetails
+	err = s.client.makeRequest(ctx, "GET", endpoint, nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get install details: %w", err)
+	}
+	return resp, nil
+}
+
+// DownloadQRCodes downloads a zip archive with the installation QR code of
+// every eSIM assigned by an order. When reference is empty the given ICCIDs
+// are downloaded instead.
+func (s *ESIMService) DownloadQRCodes(ctx context.Context, reference string, iccids ...ICCID) ([]byte, error) {
+	endpoint, err := assignmentsEndpoint(reference, iccids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download QR codes: %w", err)
+	}
+
+	archive, err := s.client.makeRawRequest(ctx, "GET", endpoint, "application/zip")
+	if err != nil {
+		return nil, fmt.Errorf("failed to download QR codes: %w", err)
+	}
+	return archive, nil
+}
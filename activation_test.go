@@ -0,0 +1,140 @@
+package esimgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestActivationCode(t *testing.T) {
+	tests := []struct {
+		smdp       string
+		matchingID string
+		want       string
+		wantErr    bool
+	}{
+		{"rsp.truphone.com", "QRF-SPEEDTEST", "LPA:1$rsp.truphone.com$QRF-SPEEDTEST", false},
+		{" smdp.io ", " K2-1WE6FV-1GHJ4YQ ", "LPA:1$smdp.io$K2-1WE6FV-1GHJ4YQ", false},
+		{"smdp.io", "", "LPA:1$smdp.io$", false},
+		{"", "K2-1WE6FV-1GHJ4YQ", "", true},
+		{"smdp.io", "K2$1WE6FV", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ActivationCode(tt.smdp, tt.matchingID)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidActivationCode) {
+				t.Errorf("ActivationCode(%q, %q): expected ErrInvalidActivationCode, got %v", tt.smdp, tt.matchingID, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ActivationCode(%q, %q) = %q, %v; expected %q", tt.smdp, tt.matchingID, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseActivationCode(t *testing.T) {
+	tests := []struct {
+		code       string
+		smdp       string
+		matchingID string
+		wantErr    bool
+	}{
+		{"LPA:1$rsp.truphone.com$QRF-SPEEDTEST", "rsp.truphone.com", "QRF-SPEEDTEST", false},
+		{"lpa:1$smdp.io$K2-1WE6FV-1GHJ4YQ$$1", "smdp.io", "K2-1WE6FV-1GHJ4YQ", false},
+		{"LPA:1$smdp.io", "smdp.io", "", false},
+		{"LPA:1$$K2", "", "", true},
+		{"https://smdp.io", "", "", true},
+	}
+
+	for _, tt := range tests {
+		smdp, matchingID, err := ParseActivationCode(tt.code)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidActivationCode) {
+				t.Errorf("ParseActivationCode(%q): expected ErrInvalidActivationCode, got %v", tt.code, err)
+			}
+			continue
+		}
+		if err != nil || smdp != tt.smdp || matchingID != tt.matchingID {
+			t.Errorf("ParseActivationCode(%q) = %q, %q, %v", tt.code, smdp, matchingID, err)
+		}
+	}
+}
+
+func TestESIMQRCode(t *testing.T) {
+	esim := &ESIM{
+		ICCID:       "8944500102198304826",
+		SMDPAddress: "smdp.io",
+		MatchingID:  "K2-1WE6FV-1GHJ4YQ",
+	}
+
+	q, err := esim.QRCode()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	decoded, err := decodeQRForTest(q)
+	if err != nil {
+		t.Fatalf("Failed to decode QR code: %v", err)
+	}
+	if string(decoded) != "LPA:1$smdp.io$K2-1WE6FV-1GHJ4YQ" {
+		t.Errorf("Unexpected QR code content %q", decoded)
+	}
+
+	if _, err := (&ESIM{ICCID: "8944500102198304826"}).QRCode(); err == nil {
+		t.Error("Expected an error for an eSIM without SM-DP+ address")
+	}
+}
+
+func TestESIMInstallDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/esims/assignments" {
+			t.Errorf("Expected path '/esims/assignments', got '%s'", r.URL.Path)
+		}
+
+		switch r.Header.Get("Accept") {
+		case "application/json":
+			if got := r.URL.RawQuery; got != "reference=order-123" {
+				t.Errorf("Expected query 'reference=order-123', got '%s'", got)
+			}
+			w.Write([]byte(`[{"iccid":"8944500102198304826","matchingId":"K2-1WE6FV-1GHJ4YQ","smdpAddress":"smdp.io"}]`))
+		case "application/zip":
+			if got := r.URL.RawQuery; got != "iccids=8944500102198304826%2C8944500102198304834" {
+				t.Errorf("Unexpected query '%s'", got)
+			}
+			w.Header().Set("Content-Type", "application/zip")
+			w.Write([]byte("PK\x03\x04zip"))
+		default:
+			t.Errorf("Unexpected Accept header '%s'", r.Header.Get("Accept"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	details, err := client.ESIMs.GetInstallDetails(ctx, "order-123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(details) != 1 {
+		t.Fatalf("Expected 1 install detail, got %d", len(details))
+	}
+	if code, _ := details[0].ActivationCode(); code != "LPA:1$smdp.io$K2-1WE6FV-1GHJ4YQ" {
+		t.Errorf("Unexpected activation code '%s'", code)
+	}
+
+	archive, err := client.ESIMs.DownloadQRCodes(ctx, "", "8944500102198304826", "8944500102198304834")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(archive) != "PK\x03\x04zip" {
+		t.Errorf("Unexpected archive %q", archive)
+	}
+
+	if _, err := client.ESIMs.DownloadQRCodes(ctx, ""); err == nil {
+		t.Error("Expected an error without reference or ICCIDs")
+	}
+}
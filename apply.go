@@ -0,0 +1,274 @@
+package esimgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultApplyConcurrency is the number of bundles applied at once when
+// ApplyBatchOptions does not set one
+const defaultApplyConcurrency = 4
+
+// ErrBatchStopped is reported for the jobs that were not run because an
+// earlier job failed and ApplyBatchOptions.StopOnError was set
+var ErrBatchStopped = errors.New("esimgo: batch stopped after an earlier failure")
+
+// ApplyBundleJob describes one bundle application of a batch. An empty
+// ICCID applies the bundle to a new eSIM.
+type ApplyBundleJob struct {
+	ICCID  ICCID
+	Bundle string
+	Repeat int
+}
+
+// ApplyBatchOptions controls how ApplyBundleBatch runs
+type ApplyBatchOptions struct {
+	// Concurrency is the maximum number of applications in flight
+	Concurrency int
+	// StopOnError skips the jobs not started yet after the first failure.
+	// Jobs already in flight are left to finish, since the API may have
+	// applied their bundles already.
+	StopOnError bool
+}
+
+// ApplyBundleResult represents the outcome of one job of a batch
+type ApplyBundleResult struct {
+	Job      ApplyBundleJob
+	Response *ApplyBundleResponse
+	Err      error
+}
+
+// BulkApplyResult represents the outcome of applying bundles in a batch.
+// Results are in the same order as the jobs.
+type BulkApplyResult struct {
+	Results []ApplyBundleResult
+}
+
+// Succeeded returns the results of the jobs that were applied
+func (r *BulkApplyResult) Succeeded() []ApplyBundleResult {
+	var succeeded []ApplyBundleResult
+	for _, result := range r.Results {
+		if result.Err == nil {
+			succeeded = append(succeeded, result)
+		}
+	}
+	return succeeded
+}
+
+// Failed returns the results of the jobs that failed or were not run
+func (r *BulkApplyResult) Failed() []ApplyBundleResult {
+	var failed []ApplyBundleResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Err returns nil when every job succeeded, or an error wrapping the first
+// failure otherwise
+func (r *BulkApplyResult) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d bundle applications failed: %w", len(failed), len(r.Results), failed[0].Err)
+}
+
+// ApplyBundleBatch applies bundles to many eSIMs with bounded concurrency
+// and reports the outcome of each job. A failed job does not stop the
+// others unless opts.StopOnError is set; once ctx is done the remaining
+// jobs fail with the context error.
+func (s *ESIMService) ApplyBundleBatch(ctx context.Context, jobs []ApplyBundleJob, opts *ApplyBatchOptions) *BulkApplyResult {
+	concurrency := defaultApplyConcurrency
+	stopOnError := false
+	if opts != nil {
+		if opts.Concurrency > 0 {
+			concurrency = opts.Concurrency
+		}
+		stopOnError = opts.StopOnError
+	}
+	concurrency = min(concurrency, len(jobs))
+
+	result := &BulkApplyResult{Results: make([]ApplyBundleResult, len(jobs))}
+	indexes := make(chan int)
+	var stopped atomic.Bool
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result.Results[i] = s.applyJob(ctx, jobs[i], &stopped)
+				if result.Results[i].Err != nil && stopOnError {
+					stopped.Store(true)
+				}
+			}
+		}()
+	}
+	for i := range jobs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return result
+}
+
+// applyJob runs one job of a batch
+func (s *ESIMService) applyJob(ctx context.Context, job ApplyBundleJob, stopped *atomic.Bool) ApplyBundleResult {
+	result := ApplyBundleResult{Job: job}
+	switch {
+	case stopped.Load():
+		result.Err = ErrBatchStopped
+	case ctx.Err() != nil:
+		result.Err = ctx.Err()
+	case job.Bundle == "":
+		result.Err = fmt.Errorf("failed to apply bundle: %w: bundle name is empty", ErrValidation)
+	default:
+		result.Response, result.Err = s.ApplyBundle(ctx, &ApplyBundleRequest{
+			Name:   job.Bundle,
+			ICCID:  job.ICCID,
+			Repeat: job.Repeat,
+		})
+	}
+	return result
+}
+
+// ApplyState classifies the status of an eSIM in a bundle application
+type ApplyState string
+
+// Apply states
+const (
+	ApplyStatePending   ApplyState = "pending"
+	ApplyStateSucceeded ApplyState = "succeeded"
+	ApplyStateFailed    ApplyState = "failed"
+	// ApplyStateUnknown is a status whose text is not recognised
+	ApplyStateUnknown ApplyState = "unknown"
+)
+
+// State classifies the free-text status returned by the API by its words.
+// Failures are checked first and pending states last, so that statuses such
+// as "Processing failed" or "Successfully processed" are final.
+func (b ESIMBundle) State() ApplyState {
+	has := wordMatcher(b.Status)
+	switch {
+	case has("not", "fail", "failed", "failure", "error", "rejected", "declined", "cancelled", "canceled"):
+		return ApplyStateFailed
+	case has("success", "successful", "successfully", "succeeded", "applied", "complete", "completed", "processed", "done"):
+		return ApplyStateSucceeded
+	case has("processing", "pending", "queued", "progress", "waiting", "started"):
+		return ApplyStatePending
+	}
+	return ApplyStateUnknown
+}
+
+// GetApplyStatus retrieves the status of the eSIMs of a bundle application
+func (s *ESIMService) GetApplyStatus(ctx context.Context, applyReference string) (*ApplyBundleResponse, error) {
+	var resp ApplyBundleResponse
+	err := s.client.makeRequest(ctx, "GET", "/esims/apply/"+url.PathEscape(applyReference), nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get apply status: %w", err)
+	}
+	if resp.ApplyReference == "" {
+		resp.ApplyReference = applyReference
+	}
+	return &resp, nil
+}
+
+// WaitOptions controls how WaitForApply polls
+type WaitOptions struct {
+	// Interval is the delay before the second poll, 1 second by default. It
+	// doubles after every poll.
+	Interval time.Duration
+	// MaxInterval caps the delay between polls, 30 seconds by default
+	MaxInterval time.Duration
+	// AcceptUnknown stops polling once no eSIM is pending, treating
+	// unrecognised statuses as final. By default WaitForApply keeps polling
+	// until every eSIM has succeeded or failed.
+	AcceptUnknown bool
+}
+
+// ApplyReport consolidates the outcome of a bundle application
+type ApplyReport struct {
+	ApplyReference string
+	Succeeded      []ESIMBundle
+	Failed         []ESIMBundle
+	Pending        []ESIMBundle
+	// Unknown holds the eSIMs whose final status is not recognised
+	Unknown []ESIMBundle
+	// Polls is the number of status requests made
+	Polls int
+}
+
+// Complete reports whether the application lists at least one eSIM and
+// every eSIM has either succeeded or failed
+func (r *ApplyReport) Complete() bool {
+	return len(r.Pending) == 0 && len(r.Unknown) == 0 && len(r.Succeeded)+len(r.Failed) > 0
+}
+
+// settled reports whether the application lists at least one eSIM and none
+// is pending, whether or not its status is recognised
+func (r *ApplyReport) settled() bool {
+	return len(r.Pending) == 0 && len(r.Succeeded)+len(r.Failed)+len(r.Unknown) > 0
+}
+
+// newApplyReport groups the eSIMs of a status response by state
+func newApplyReport(resp *ApplyBundleResponse, polls int) *ApplyReport {
+	report := &ApplyReport{ApplyReference: resp.ApplyReference, Polls: polls}
+	for _, esim := range resp.ESIMs {
+		switch esim.State() {
+		case ApplyStatePending:
+			report.Pending = append(report.Pending, esim)
+		case ApplyStateSucceeded:
+			report.Succeeded = append(report.Succeeded, esim)
+		case ApplyStateFailed:
+			report.Failed = append(report.Failed, esim)
+		default:
+			report.Unknown = append(report.Unknown, esim)
+		}
+	}
+	return report
+}
+
+// WaitForApply polls the status of a bundle application with exponential
+// backoff until the report is Complete. An empty list of eSIMs is polled
+// again, since the API may not have registered the application yet. When
+// ctx is done first, the report of the last poll is returned along with
+// the context error.
+func (s *ESIMService) WaitForApply(ctx context.Context, applyReference string, opts *WaitOptions) (*ApplyReport, error) {
+	interval, maxInterval := time.Second, 30*time.Second
+	acceptUnknown := false
+	if opts != nil {
+		if opts.Interval > 0 {
+			interval = opts.Interval
+		}
+		if opts.MaxInterval > 0 {
+			maxInterval = opts.MaxInterval
+		}
+		acceptUnknown = opts.AcceptUnknown
+	}
+
+	var report *ApplyReport
+	for polls := 1; ; polls++ {
+		resp, err := s.GetApplyStatus(ctx, applyReference)
+		if err != nil {
+			return report, err
+		}
+		report = newApplyReport(resp, polls)
+		if report.Complete() || acceptUnknown && report.settled() {
+			return report, nil
+		}
+
+		if err := sleep(ctx, interval); err != nil {
+			return report, fmt.Errorf("failed to wait for apply %s: %w", applyReference, err)
+		}
+		interval = min(interval*2, maxInterval)
+	}
+}
@@ -0,0 +1,312 @@
+package esimgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestESIMApplyBundleBatch(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			peak := maxInFlight.Load()
+			if current <= peak || maxInFlight.CompareAndSwap(peak, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		var req ApplyBundleRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.ICCID == "8944500102198304822" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"bundle not available"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(ApplyBundleResponse{
+			ESIMs:          []ESIMBundle{{ICCID: req.ICCID, Bundle: req.Name, Status: "Successfully Applied Bundle"}},
+			ApplyReference: "ref-" + req.ICCID.String(),
+		})
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+	jobs := []ApplyBundleJob{
+		{ICCID: "8944500102198304820", Bundle: "esim_1GB_7D_GB_V2"},
+		{ICCID: "8944500102198304821", Bundle: "esim_1GB_7D_GB_V2", Repeat: 2},
+		{ICCID: "8944500102198304822", Bundle: "esim_1GB_7D_GB_V2"},
+		{ICCID: "8944500102198304823", Bundle: ""},
+		{ICCID: "8944500102198304824", Bundle: "esim_1GB_7D_GB_V2"},
+		{ICCID: "8944500102198304825", Bundle: "esim_1GB_7D_GB_V2"},
+	}
+
+	result := client.ESIMs.ApplyBundleBatch(context.Background(), jobs, &ApplyBatchOptions{Concurrency: 2})
+	if len(result.Results) != len(jobs) {
+		t.Fatalf("Expected %d results, got %d", len(jobs), len(result.Results))
+	}
+	for i, r := range result.Results {
+		if r.Job != jobs[i] {
+			t.Errorf("Expected result %d to be for job %+v, got %+v", i, jobs[i], r.Job)
+		}
+	}
+	if got := maxInFlight.Load(); got > 2 {
+		t.Errorf("Expected at most 2 requests in flight, got %d", got)
+	}
+	if got := len(result.Succeeded()); got != 4 {
+		t.Errorf("Expected 4 successes, got %d", got)
+	}
+	if result.Results[1].Response.ApplyReference != "ref-8944500102198304821" {
+		t.Errorf("Unexpected response %+v", result.Results[1].Response)
+	}
+	if !errors.Is(result.Results[2].Err, ErrValidation) {
+		t.Errorf("Expected API validation error, got %v", result.Results[2].Err)
+	}
+	if !errors.Is(result.Results[3].Err, ErrValidation) {
+		t.Errorf("Expected client-side validation error, got %v", result.Results[3].Err)
+	}
+	if err := result.Err(); err == nil || !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected batch error wrapping ErrValidation, got %v", err)
+	}
+}
+
+func TestESIMApplyBundleBatchStopOnError(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ApplyBundleRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		requested = append(requested, req.ICCID.String())
+		mu.Unlock()
+		if req.ICCID == "8944500102198304821" {
+			w.WriteHeader(http.StatusPaymentRequired)
+			w.Write([]byte(`{"message":"insufficient balance"}`))
+			return
+		}
+		w.Write([]byte(`{"esims":[],"applyReference":"ref"}`))
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+	jobs := []ApplyBundleJob{
+		{ICCID: "8944500102198304820", Bundle: "esim_1GB_7D_GB_V2"},
+		{ICCID: "8944500102198304821", Bundle: "esim_1GB_7D_GB_V2"},
+		{ICCID: "8944500102198304822", Bundle: "esim_1GB_7D_GB_V2"},
+		{ICCID: "8944500102198304823", Bundle: "esim_1GB_7D_GB_V2"},
+	}
+
+	result := client.ESIMs.ApplyBundleBatch(context.Background(), jobs, &ApplyBatchOptions{Concurrency: 1, StopOnError: true})
+	if len(requested) != 2 {
+		t.Errorf("Expected 2 requests before stopping, got %v", requested)
+	}
+	if result.Results[0].Err != nil {
+		t.Errorf("Expected first job to succeed, got %v", result.Results[0].Err)
+	}
+	if !errors.Is(result.Results[1].Err, ErrInsufficientBalance) {
+		t.Errorf("Expected ErrInsufficientBalance, got %v", result.Results[1].Err)
+	}
+	for _, r := range result.Results[2:] {
+		if !errors.Is(r.Err, ErrBatchStopped) {
+			t.Errorf("Expected ErrBatchStopped for %s, got %v", r.Job.ICCID, r.Err)
+		}
+	}
+}
+
+func TestESIMApplyBundleBatchStopOnErrorLetsInFlightJobsFinish(t *testing.T) {
+	failed := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ApplyBundleRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.ICCID == "8944500102198304821" {
+			w.WriteHeader(http.StatusPaymentRequired)
+			w.Write([]byte(`{"message":"insufficient balance"}`))
+			close(failed)
+			return
+		}
+		// Still in flight when the other job fails
+		<-failed
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{"esims":[],"applyReference":"ref"}`))
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+	jobs := []ApplyBundleJob{
+		{ICCID: "8944500102198304820", Bundle: "esim_1GB_7D_GB_V2"},
+		{ICCID: "8944500102198304821", Bundle: "esim_1GB_7D_GB_V2"},
+		{ICCID: "8944500102198304822", Bundle: "esim_1GB_7D_GB_V2"},
+	}
+
+	result := client.ESIMs.ApplyBundleBatch(context.Background(), jobs, &ApplyBatchOptions{Concurrency: 2, StopOnError: true})
+	if result.Results[0].Err != nil || result.Results[0].Response == nil {
+		t.Errorf("Expected the in-flight job to finish, got %v", result.Results[0].Err)
+	}
+	if !errors.Is(result.Results[1].Err, ErrInsufficientBalance) {
+		t.Errorf("Expected ErrInsufficientBalance, got %v", result.Results[1].Err)
+	}
+	if !errors.Is(result.Results[2].Err, ErrBatchStopped) {
+		t.Errorf("Expected ErrBatchStopped for the unstarted job, got %v", result.Results[2].Err)
+	}
+}
+
+func TestESIMApplyBundleBatchCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request once the context is cancelled")
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result := client.ESIMs.ApplyBundleBatch(ctx, []ApplyBundleJob{
+		{ICCID: "8944500102198304820", Bundle: "esim_1GB_7D_GB_V2"},
+		{ICCID: "8944500102198304821", Bundle: "esim_1GB_7D_GB_V2"},
+	}, nil)
+	for _, r := range result.Results {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", r.Err)
+		}
+	}
+}
+
+func TestESIMBundleState(t *testing.T) {
+	tests := []struct {
+		status string
+		want   ApplyState
+	}{
+		{"Processing", ApplyStatePending},
+		{"Queued", ApplyStatePending},
+		{"IN_PROGRESS", ApplyStatePending},
+		{"Successfully Applied Bundle", ApplyStateSucceeded},
+		{"Successfully processed", ApplyStateSucceeded},
+		{"Bundle Not Applied", ApplyStateFailed},
+		{"Failed", ApplyStateFailed},
+		{"Processing failed", ApplyStateFailed},
+		{"Unprocessable", ApplyStateUnknown},
+		{"Something new", ApplyStateUnknown},
+	}
+	for _, tt := range tests {
+		if got := (ESIMBundle{Status: tt.status}).State(); got != tt.want {
+			t.Errorf("%q: expected %s, got %s", tt.status, tt.want, got)
+		}
+	}
+}
+
+func TestESIMWaitForApply(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/esims/apply/ref-1" {
+			t.Errorf("Expected GET /esims/apply/ref-1, got %s %s", r.Method, r.URL.Path)
+		}
+		second := "Processing"
+		if polls.Add(1) >= 3 {
+			second = "Failed"
+		}
+		w.Write([]byte(`{"applyReference":"ref-1","esims":[` +
+			`{"iccid":"8944500102198304820","bundle":"esim_1GB_7D_GB_V2","status":"Successfully Applied Bundle"},` +
+			`{"iccid":"8944500102198304821","bundle":"esim_1GB_7D_GB_V2","status":"` + second + `"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+	report, err := client.ESIMs.WaitForApply(context.Background(), "ref-1", &WaitOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Polls != 3 || !report.Complete() {
+		t.Errorf("Expected a complete report after 3 polls, got %+v", report)
+	}
+	if len(report.Succeeded) != 1 || len(report.Failed) != 1 || report.Failed[0].ICCID != "8944500102198304821" {
+		t.Errorf("Unexpected report %+v", report)
+	}
+}
+
+func TestESIMWaitForApplyTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"esims":[{"iccid":"8944500102198304820","status":"Processing"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	report, err := client.ESIMs.WaitForApply(ctx, "ref-1", &WaitOptions{Interval: 10 * time.Millisecond, MaxInterval: 20 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if report == nil || report.Complete() || len(report.Pending) != 1 || report.ApplyReference != "ref-1" {
+		t.Errorf("Expected the last pending report, got %+v", report)
+	}
+}
+
+func TestESIMWaitForApplyKeepsPolling(t *testing.T) {
+	tests := []struct {
+		name          string
+		responses     []string
+		acceptUnknown bool
+		polls         int
+		complete      bool
+	}{
+		{
+			name: "empty list",
+			responses: []string{
+				`{"esims":[]}`,
+				`{"esims":[{"iccid":"8944500102198304820","status":"Successfully Applied Bundle"}]}`,
+			},
+			polls:    2,
+			complete: true,
+		},
+		{
+			name: "unknown status",
+			responses: []string{
+				`{"esims":[{"iccid":"8944500102198304820","status":"Something new"}]}`,
+				`{"esims":[{"iccid":"8944500102198304820","status":"Failed"}]}`,
+			},
+			polls:    2,
+			complete: true,
+		},
+		{
+			name: "unknown status accepted",
+			responses: []string{
+				`{"esims":[{"iccid":"8944500102198304820","status":"Something new"}]}`,
+				`{"esims":[{"iccid":"8944500102198304820","status":"Failed"}]}`,
+			},
+			acceptUnknown: true,
+			polls:         1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := int(polls.Add(1)) - 1
+				w.Write([]byte(tt.responses[min(i, len(tt.responses)-1)]))
+			}))
+			defer server.Close()
+
+			client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+			report, err := client.ESIMs.WaitForApply(context.Background(), "ref-1", &WaitOptions{
+				Interval:      time.Millisecond,
+				AcceptUnknown: tt.acceptUnknown,
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if report.Polls != tt.polls || report.Complete() != tt.complete {
+				t.Errorf("Expected %d polls and complete %t, got %+v", tt.polls, tt.complete, report)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
 // CatalogueService handles catalogue-related operations
@@ -29,7 +30,11 @@ type CatalogueBundle struct {
 	Autostart      bool      `json:"autostart"`
 	Unlimited      bool      `json:"unlimited"`
 	RoamingEnabled []Country `json:"roamingEnabled"`
-	Price          float64   `json:"price"`
+	// Price is in the currency of the organisation, which the catalogue
+	// does not return, so its Currency is empty and every decimal is kept.
+	// Attach it with WithCurrency before comparing the price with other
+	// amounts.
+	Price Money `json:"price"`
 }
 
 // ListCatalogueRequest represents query parameters for listing catalogue bundles
@@ -42,29 +47,147 @@ type ListCatalogueRequest struct {
 	Group       string `json:"group,omitempty"`
 	Countries   string `json:"countries,omitempty"`
 	Region      string `json:"region,omitempty"`
+	// CountryISOs filters by several ISO country codes at once; it is merged
+	// with Countries into a single comma separated "countries" parameter
+	CountryISOs []string `json:"countryISOs,omitempty"`
+	// FilterLocally re-applies the filters to the returned bundles, as a
+	// fallback for API deployments that ignore some of the query parameters
+	FilterLocally bool `json:"-"`
 }
 
-type Bundles struct {
-	Bundles []CatalogueBundle `json:"bundles"`
+// countryCodes returns the normalised, de-duplicated ISO codes to filter by
+func (r *ListCatalogueRequest) countryCodes() []string {
+	var codes []string
+	seen := make(map[string]bool)
+	add := func(code string) {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" || seen[code] {
+			return
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+
+	for _, code := range strings.Split(r.Countries, ",") {
+		add(code)
+	}
+	for _, code := range r.CountryISOs {
+		add(code)
+	}
+	return codes
 }
 
-// List retrieves all bundles available in the catalogue
-func (s *CatalogueService) List(ctx context.Context, req *ListCatalogueRequest) (*Bundles, error) {
+// values encodes the request as catalogue query parameters
+func (r *ListCatalogueRequest) values() url.Values {
 	params := url.Values{}
 
-	if req.Page > 0 {
-		params.Set("page", strconv.Itoa(req.Page))
+	if r.Page > 0 {
+		params.Set("page", strconv.Itoa(r.Page))
+	}
+	if r.PerPage > 0 {
+		params.Set("perPage", strconv.Itoa(r.PerPage))
+	}
+	if r.Direction != "" {
+		params.Set("direction", r.Direction)
+	}
+	if r.OrderBy != "" {
+		params.Set("orderBy", r.OrderBy)
+	}
+	if r.Description != "" {
+		params.Set("description", r.Description)
+	}
+	if r.Group != "" {
+		params.Set("group", r.Group)
+	}
+	if codes := r.countryCodes(); len(codes) > 0 {
+		params.Set("countries", strings.Join(codes, ","))
+	}
+	if r.Region != "" {
+		params.Set("region", r.Region)
+	}
+
+	return params
+}
+
+// Matches reports whether a bundle satisfies every filter set on the request
+func (r *ListCatalogueRequest) Matches(bundle CatalogueBundle) bool {
+	if r.Description != "" &&
+		!strings.Contains(strings.ToLower(bundle.Description), strings.ToLower(r.Description)) {
+		return false
 	}
-	if req.PerPage > 0 {
-		params.Set("perPage", strconv.Itoa(req.PerPage))
+
+	if r.Group != "" {
+		found := false
+		for _, group := range bundle.Groups {
+			if strings.EqualFold(group, r.Group) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
 	}
-	if req.Direction != "" {
-		params.Set("direction", req.Direction)
+
+	if codes := r.countryCodes(); len(codes) > 0 {
+		found := false
+		for _, country := range bundle.Countries {
+			for _, code := range codes {
+				if strings.EqualFold(country.ISO, code) {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			return false
+		}
 	}
-	if req.OrderBy != "" {
-		params.Set("orderBy", req.OrderBy)
+
+	if r.Region != "" {
+		found := false
+		for _, country := range bundle.Countries {
+			if strings.EqualFold(country.Region, r.Region) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
 	}
 
+	return true
+}
+
+// Bundles represents a page of catalogue bundles
+type Bundles struct {
+	Bundles []CatalogueBundle `json:"bundles"`
+	PageInfo
+}
+
+// Filter returns the bundles that match every filter set on req
+func (b *Bundles) Filter(req *ListCatalogueRequest) []CatalogueBundle {
+	if req == nil {
+		return b.Bundles
+	}
+
+	filtered := make([]CatalogueBundle, 0, len(b.Bundles))
+	for _, bundle := range b.Bundles {
+		if req.Matches(bundle) {
+			filtered = append(filtered, bundle)
+		}
+	}
+	return filtered
+}
+
+// List retrieves all bundles available in the catalogue
+func (s *CatalogueService) List(ctx context.Context, req *ListCatalogueRequest) (*Bundles, error) {
+	if req == nil {
+		req = &ListCatalogueRequest{}
+	}
+	params := req.values()
+
 	endpoint := "/catalogue"
 	if len(params) > 0 {
 		endpoint += "?" + params.Encode()
@@ -75,5 +198,35 @@ func (s *CatalogueService) List(ctx context.Context, req *ListCatalogueRequest)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list catalogue: %w", err)
 	}
+	if req.FilterLocally {
+		resp.Bundles = resp.Filter(req)
+	}
 	return &resp, nil
 }
+
+// ListAll returns an iterator over every catalogue bundle matching req,
+// starting at req.Page and fetching further pages lazily. With
+// FilterLocally, bundles are filtered after paging, so a page without
+// matches does not end the iteration.
+func (s *CatalogueService) ListAll(ctx context.Context, req *ListCatalogueRequest) *Iterator[CatalogueBundle] {
+	base := ListCatalogueRequest{}
+	if req != nil {
+		base = *req
+	}
+
+	fetch := base
+	fetch.FilterLocally = false
+	it := newIterator(ctx, base.Page, func(ctx context.Context, page int) ([]CatalogueBundle, PageInfo, error) {
+		pageReq := fetch
+		pageReq.Page = page
+		resp, err := s.List(ctx, &pageReq)
+		if err != nil {
+			return nil, PageInfo{}, err
+		}
+		return resp.Bundles, resp.PageInfo, nil
+	})
+	if base.FilterLocally {
+		it = it.filter(base.Matches)
+	}
+	return it
+}
@@ -0,0 +1,242 @@
+package esimgo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCatalogueListQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		req   *ListCatalogueRequest
+		query string
+	}{
+		{
+			name:  "nil request",
+			req:   nil,
+			query: "",
+		},
+		{
+			name:  "pagination and ordering",
+			req:   &ListCatalogueRequest{Page: 2, PerPage: 50, Direction: DirectionDesc, OrderBy: "price"},
+			query: "direction=desc&orderBy=price&page=2&perPage=50",
+		},
+		{
+			name:  "description and group",
+			req:   &ListCatalogueRequest{Description: "1GB", Group: "Standard Fixed"},
+			query: "description=1GB&group=Standard+Fixed",
+		},
+		{
+			name:  "single country",
+			req:   &ListCatalogueRequest{Countries: "ES"},
+			query: "countries=ES",
+		},
+		{
+			name:  "typed countries merged and normalised",
+			req:   &ListCatalogueRequest{Countries: "es, FR", CountryISOs: []string{"fr", "it"}},
+			query: "countries=ES%2CFR%2CIT",
+		},
+		{
+			name:  "region",
+			req:   &ListCatalogueRequest{Region: "Europe", PerPage: 10},
+			query: "perPage=10&region=Europe",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/catalogue" {
+					t.Errorf("Expected path '/catalogue', got '%s'", r.URL.Path)
+				}
+				gotQuery = r.URL.RawQuery
+				json.NewEncoder(w).Encode(Bundles{})
+			}))
+			defer server.Close()
+
+			client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+
+			if _, err := client.Catalogue.List(context.Background(), tt.req); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if gotQuery != tt.query {
+				t.Errorf("Expected query '%s', got '%s'", tt.query, gotQuery)
+			}
+		})
+	}
+}
+
+func TestCatalogueFilterLocally(t *testing.T) {
+	bundles := []CatalogueBundle{
+		{
+			Name:        "esim_1GB_7D_ES_V2",
+			Description: "eSIM, 1GB, 7 Days, Spain, V2",
+			Groups:      []string{"Standard Fixed"},
+			Countries:   []Country{{Name: "Spain", Region: "Europe", ISO: "ES"}},
+		},
+		{
+			Name:        "esim_1GB_7D_US_V2",
+			Description: "eSIM, 1GB, 7 Days, United States, V2",
+			Groups:      []string{"Standard Fixed"},
+			Countries:   []Country{{Name: "United States", Region: "North America", ISO: "US"}},
+		},
+		{
+			Name:        "esim_UL_1D_EU_V2",
+			Description: "eSIM, Unlimited, 1 Day, Europe, V2",
+			Groups:      []string{"Standard Unlimited Essential"},
+			Countries: []Country{
+				{Name: "France", Region: "Europe", ISO: "FR"},
+				{Name: "Spain", Region: "Europe", ISO: "ES"},
+			},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate an API that ignores every filter
+		json.NewEncoder(w).Encode(Bundles{Bundles: bundles})
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+
+	tests := []struct {
+		name string
+		req  *ListCatalogueRequest
+		want []string
+	}{
+		{"country", &ListCatalogueRequest{Countries: "es"}, []string{"esim_1GB_7D_ES_V2", "esim_UL_1D_EU_V2"}},
+		{"typed countries", &ListCatalogueRequest{CountryISOs: []string{"US", "FR"}}, []string{"esim_1GB_7D_US_V2", "esim_UL_1D_EU_V2"}},
+		{"group", &ListCatalogueRequest{Group: "standard fixed"}, []string{"esim_1GB_7D_ES_V2", "esim_1GB_7D_US_V2"}},
+		{"region", &ListCatalogueRequest{Region: "North America"}, []string{"esim_1GB_7D_US_V2"}},
+		{"description", &ListCatalogueRequest{Description: "unlimited"}, []string{"esim_UL_1D_EU_V2"}},
+		{"combined", &ListCatalogueRequest{Countries: "ES", Group: "Standard Fixed"}, []string{"esim_1GB_7D_ES_V2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.FilterLocally = true
+			resp, err := client.Catalogue.List(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(resp.Bundles) != len(tt.want) {
+				t.Fatalf("Expected %d bundles, got %d", len(tt.want), len(resp.Bundles))
+			}
+			for i, name := range tt.want {
+				if resp.Bundles[i].Name != name {
+					t.Errorf("Expected bundle %d to be '%s', got '%s'", i, name, resp.Bundles[i].Name)
+				}
+			}
+		})
+	}
+}
+
+func TestCatalogueListAll(t *testing.T) {
+	pages := [][]string{
+		{"bundle_1", "bundle_2"},
+		{"bundle_3", "bundle_4"},
+		{"bundle_5"},
+	}
+
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		requested = append(requested, page)
+
+		var resp Bundles
+		resp.PageCount = len(pages)
+		resp.Rows = 5
+		resp.PageSize = 2
+		switch page {
+		case "1", "2", "3":
+			for _, name := range pages[page[0]-'1'] {
+				resp.Bundles = append(resp.Bundles, CatalogueBundle{Name: name})
+			}
+		default:
+			t.Errorf("Unexpected page '%s'", page)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+
+	t.Run("all pages", func(t *testing.T) {
+		requested = nil
+		bundles, err := client.Catalogue.ListAll(context.Background(), &ListCatalogueRequest{PerPage: 2}).Collect()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(bundles) != 5 {
+			t.Fatalf("Expected 5 bundles, got %d", len(bundles))
+		}
+		if bundles[4].Name != "bundle_5" {
+			t.Errorf("Expected last bundle 'bundle_5', got '%s'", bundles[4].Name)
+		}
+		if len(requested) != 3 {
+			t.Errorf("Expected 3 page requests, got %v", requested)
+		}
+	})
+
+	t.Run("stop early", func(t *testing.T) {
+		requested = nil
+		it := client.Catalogue.ListAll(context.Background(), nil)
+		for i := 0; i < 2 && it.Next(); i++ {
+		}
+		if len(requested) != 1 {
+			t.Errorf("Expected a single page request, got %v", requested)
+		}
+		if it.PageInfo().PageCount != 3 {
+			t.Errorf("Expected page count 3, got %d", it.PageInfo().PageCount)
+		}
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		requested = nil
+		ctx, cancel := context.WithCancel(context.Background())
+		it := client.Catalogue.ListAll(ctx, nil)
+		if !it.Next() {
+			t.Fatalf("Expected a first bundle, got error %v", it.Err())
+		}
+		cancel()
+		if it.Next() {
+			t.Error("Expected iteration to stop after cancellation")
+		}
+		if it.Err() != context.Canceled {
+			t.Errorf("Expected context.Canceled, got %v", it.Err())
+		}
+	})
+}
+
+func TestCatalogueListAllSkipsFilteredPages(t *testing.T) {
+	pages := map[string]string{
+		"1": `{"bundles":[{"name":"bundle_us","countries":[{"iso":"US"}]}]}`,
+		"2": `{"bundles":[{"name":"bundle_ar","countries":[{"iso":"AR"}]}]}`,
+		"3": `{"bundles":[]}`,
+	}
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(pages[r.URL.Query().Get("page")]))
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+	bundles, err := client.Catalogue.ListAll(context.Background(), &ListCatalogueRequest{
+		Countries:     "AR",
+		FilterLocally: true,
+	}).Collect()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(bundles) != 1 || bundles[0].Name != "bundle_ar" {
+		t.Errorf("Expected the bundle of page 2, got %+v", bundles)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+}
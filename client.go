@@ -6,96 +6,211 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
 )
 
+const (
+	defaultBaseURL   = "https://api.esim-go.com/v2.4"
+	defaultUserAgent = "esimgo-client-go"
+	defaultTimeout   = 30 * time.Second
+)
+
 // Client represents the eSIM Go API client
 type Client struct {
-	baseURL    string
-	apiKey     string
-	httpClient *http.Client
+	baseURL     string
+	apiKey      string
+	httpClient  *http.Client
+	timeout     time.Duration
+	userAgent   string
+	retryPolicy *RetryPolicy
+	rateLimiter *RateLimiter
+	logger      *slog.Logger
+	middleware  []Middleware
+	// orderJournal records transaction orders, if set
+	orderJournal OrderJournal
 }
 
 // NewClient creates a new eSIM Go API client
-func NewClient(apiKey string) *Client {
-	return &Client{
-		baseURL:    "https://api.esim-go.com/v2.4",
+func NewClient(apiKey string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    defaultBaseURL,
 		apiKey:     apiKey,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		userAgent:  defaultUserAgent,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	if c.timeout > 0 {
+		httpClient := *c.httpClient
+		httpClient.Timeout = c.timeout
+		c.httpClient = &httpClient
 	}
+
+	return c
 }
 
 // SetHTTPClient allows setting a custom HTTP client
+//
+// Deprecated: Use WithHTTPClient when creating the client. Setters are not
+// safe to call while requests are in flight.
 func (c *Client) SetHTTPClient(client *http.Client) {
 	c.httpClient = client
 }
 
 // SetBaseURL allows setting a custom base URL (useful for testing)
+//
+// Deprecated: Use WithBaseURL when creating the client. Setters are not
+// safe to call while requests are in flight.
 func (c *Client) SetBaseURL(baseURL string) {
 	c.baseURL = strings.TrimSuffix(baseURL, "/")
 }
 
-// makeRequest performs HTTP requests with proper authentication
+// makeRequest performs JSON requests with proper authentication, retrying
+// transient failures according to the client's RetryPolicy
 func (c *Client) makeRequest(ctx context.Context, method, endpoint string, body interface{}, result interface{}) error {
-	var reqBody io.Reader
+	var payload []byte
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonBody)
+		payload = jsonBody
+	}
+
+	respBody, err := c.execute(ctx, method, endpoint, payload, "application/json")
+	if err != nil {
+		return err
+	}
+
+	if result != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, result); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// makeRawRequest performs a request without a body and returns the raw
+// response body, for endpoints that answer with files instead of JSON
+func (c *Client) makeRawRequest(ctx context.Context, method, endpoint, accept string) ([]byte, error) {
+	return c.execute(ctx, method, endpoint, nil, accept)
+}
+
+// execute sends a request, retrying transient failures, and returns the
+// body of the first successful response
+func (c *Client) execute(ctx context.Context, method, endpoint string, payload []byte, accept string) ([]byte, error) {
+	attempts := c.retryPolicy.attempts(ctx, method)
+	for attempt := 1; ; attempt++ {
+		resp, respBody, err := c.do(ctx, method, endpoint, payload, accept)
+
+		statusCode := 0
+		var header http.Header
+		if resp != nil {
+			statusCode = resp.StatusCode
+			header = resp.Header
+		}
+		if attempt < attempts && c.retryPolicy.shouldRetry(ctx, statusCode, err) {
+			wait := c.retryPolicy.delay(attempt, header)
+			if c.logger != nil {
+				c.logger.WarnContext(ctx, "retrying eSIM Go request",
+					"method", method, "endpoint", endpoint, "attempt", attempt,
+					"status", statusCode, "error", err, "wait", wait)
+			}
+			if err := sleep(ctx, wait); err != nil {
+				return nil, fmt.Errorf("request failed: %w", err)
+			}
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode >= 400 {
+			return nil, newAPIError(method, endpoint, resp, respBody)
+		}
+
+		return respBody, nil
+	}
+}
+
+// do sends a single request and reads the whole response body
+func (c *Client) do(ctx context.Context, method, endpoint string, payload []byte, accept string) (*http.Response, []byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+endpoint, reqBody)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("X-API-Key", c.apiKey)
-	req.Header.Set("Accept", "application/json")
-	if body != nil {
+	req.Header.Set("Accept", accept)
+	if payload != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if key := idempotencyKey(ctx); key != "" {
+		req.Header.Set(idempotencyHeader, key)
+	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+	class := endpointClass(endpoint)
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.Wait(ctx, class); err != nil {
+			return nil, nil, fmt.Errorf("rate limiter: %w", err)
+		}
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	start := time.Now()
+	resp, err := c.send(req)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		if c.logger != nil {
+			c.logger.DebugContext(ctx, "eSIM Go request failed",
+				"method", method, "endpoint", endpoint, "error", err)
+		}
+		return nil, nil, fmt.Errorf("request failed: %w", err)
 	}
+	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		var apiErr APIError
-		if err := json.Unmarshal(respBody, &apiErr); err != nil {
-			return fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
-		}
-		apiErr.StatusCode = resp.StatusCode
-		return &apiErr
+	if c.logger != nil {
+		c.logger.DebugContext(ctx, "eSIM Go request",
+			"method", method, "endpoint", endpoint,
+			"status", resp.StatusCode, "duration", time.Since(start))
 	}
 
-	if result != nil && len(respBody) > 0 {
-		if err := json.Unmarshal(respBody, result); err != nil {
-			return fmt.Errorf("failed to unmarshal response: %w", err)
-		}
+	if c.rateLimiter != nil {
+		c.rateLimiter.Observe(class, resp.StatusCode, resp.Header)
 	}
 
-	return nil
-}
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
 
-// APIError represents an API error response
-type APIError struct {
-	Message    string `json:"message"`
-	StatusCode int    `json:"-"`
+	return resp, respBody, nil
 }
 
-func (e *APIError) Error() string {
-	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+// send passes the request through the middleware chain to the HTTP client
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	next := RequestFunc(c.httpClient.Do)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		next = c.middleware[i](next)
+	}
+	return next(req)
 }
 
 // Common structures used across the API
@@ -123,30 +238,30 @@ type Bundle struct {
 
 // Assignment represents a bundle assignment
 type Assignment struct {
-	ID                  string    `json:"id"`
-	CallTypeGroup       string    `json:"callTypeGroup"`
-	InitialQuantity     int64     `json:"initialQuantity"`
-	RemainingQuantity   int64     `json:"remainingQuantity"`
-	AssignmentDateTime  time.Time `json:"assignmentDateTime"`
-	AssignmentReference string    `json:"assignmentReference"`
-	BundleState         string    `json:"bundleState"`
-	Unlimited           bool      `json:"unlimited"`
+	ID                  string      `json:"id"`
+	CallTypeGroup       string      `json:"callTypeGroup"`
+	InitialQuantity     int64       `json:"initialQuantity"`
+	RemainingQuantity   int64       `json:"remainingQuantity"`
+	AssignmentDateTime  time.Time   `json:"assignmentDateTime"`
+	AssignmentReference string      `json:"assignmentReference"`
+	BundleState         BundleState `json:"bundleState"`
+	Unlimited           bool        `json:"unlimited"`
 }
 
 // ESIM represents an eSIM
 type ESIM struct {
-	ICCID                  string `json:"iccid"`
-	PIN                    string `json:"pin,omitempty"`
-	PUK                    string `json:"puk,omitempty"`
-	MatchingID             string `json:"matchingId,omitempty"`
-	SMDPAddress            string `json:"smdpAddress,omitempty"`
-	ProfileStatus          string `json:"profileStatus,omitempty"`
-	FirstInstalledDateTime int64  `json:"firstInstalledDateTime,omitempty"`
-	CustomerRef            string `json:"customerRef,omitempty"`
-	LastAction             string `json:"lastAction,omitempty"`
-	ActionDate             string `json:"actionDate,omitempty"`
-	Physical               bool   `json:"physical,omitempty"`
-	AssignedDate           string `json:"assignedDate,omitempty"`
+	ICCID                  ICCID         `json:"iccid"`
+	PIN                    string        `json:"pin,omitempty"`
+	PUK                    string        `json:"puk,omitempty"`
+	MatchingID             string        `json:"matchingId,omitempty"`
+	SMDPAddress            string        `json:"smdpAddress,omitempty"`
+	ProfileStatus          ProfileStatus `json:"profileStatus,omitempty"`
+	FirstInstalledDateTime int64         `json:"firstInstalledDateTime,omitempty"`
+	CustomerRef            string        `json:"customerRef,omitempty"`
+	LastAction             string        `json:"lastAction,omitempty"`
+	ActionDate             string        `json:"actionDate,omitempty"`
+	Physical               bool          `json:"physical,omitempty"`
+	AssignedDate           string        `json:"assignedDate,omitempty"`
 }
 
 // ESIMGoClient is the main client that provides access to all services
@@ -161,8 +276,8 @@ type ESIMGoClient struct {
 }
 
 // NewESIMGoClient creates a new complete eSIM Go API client
-func NewESIMGoClient(apiKey string) *ESIMGoClient {
-	baseClient := NewClient(apiKey)
+func NewESIMGoClient(apiKey string, opts ...Option) *ESIMGoClient {
+	baseClient := NewClient(apiKey, opts...)
 
 	return &ESIMGoClient{
 		Client:       baseClient,
@@ -177,15 +292,6 @@ func NewESIMGoClient(apiKey string) *ESIMGoClient {
 
 // Common constants for API usage
 const (
-	// Bundle states
-	BundleStateProcessing = "Processing"
-	BundleStateQueued     = "Queued"
-	BundleStateActive     = "Active"
-	BundleStateDepleted   = "Depleted"
-	BundleStateExpired    = "Expired"
-	BundleStateRevoked    = "Revoked"
-	BundleStateLapsed     = "Lapsed"
-
 	// Order types
 	OrderTypeValidate    = "validate"
 	OrderTypeTransaction = "transaction"
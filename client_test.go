@@ -75,7 +75,7 @@ func TestOrganizationService(t *testing.T) {
 			response := Organization{
 				Name:     "Test Organization",
 				Currency: "USD",
-				Balance:  1000,
+				Balance:  NewMoney(100000, "USD"),
 			}
 			json.NewEncoder(w).Encode(response)
 			return
@@ -98,8 +98,8 @@ func TestOrganizationService(t *testing.T) {
 			t.Errorf("Expected name 'Test Organization', got '%s'", org.Name)
 		}
 
-		if org.Balance != 1000 {
-			t.Errorf("Expected balance 1000, got %d", org.Balance)
+		if org.Balance != NewMoney(100000, "USD") {
+			t.Errorf("Expected balance 1000.00 USD, got %s", org.Balance)
 		}
 	}
 }
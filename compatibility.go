@@ -0,0 +1,166 @@
+package esimgo
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// CompatibilityVerdict summarises a compatibility check
+type CompatibilityVerdict string
+
+// Compatibility verdicts
+const (
+	CompatibilityCompatible   CompatibilityVerdict = "compatible"
+	CompatibilityIncompatible CompatibilityVerdict = "incompatible"
+	CompatibilityUnknown      CompatibilityVerdict = "unknown"
+)
+
+// CompatibilityResult represents the outcome of checking whether a device
+// can use an eSIM
+type CompatibilityResult struct {
+	Compatible *bool    `json:"compatible"`
+	Device     string   `json:"device,omitempty"`
+	Message    string   `json:"message,omitempty"`
+	Reasons    []string `json:"reasons,omitempty"`
+}
+
+// Verdict returns the verdict of the check, or CompatibilityUnknown when
+// the API could not tell
+func (r *CompatibilityResult) Verdict() CompatibilityVerdict {
+	switch {
+	case r.Compatible == nil:
+		return CompatibilityUnknown
+	case *r.Compatible:
+		return CompatibilityCompatible
+	default:
+		return CompatibilityIncompatible
+	}
+}
+
+// CheckCompatibility checks whether a device, identified by its model or
+// IMEI/TAC, supports an eSIM
+func (s *ESIMService) CheckCompatibility(ctx context.Context, iccid ICCID, deviceIdentifier string) (*CompatibilityResult, error) {
+	if strings.TrimSpace(deviceIdentifier) == "" {
+		return nil, fmt.Errorf("failed to check compatibility: %w: device identifier is empty", ErrValidation)
+	}
+	endpoint := "/esims/" + iccid.PathSegment() + "/compatible/" + url.PathEscape(deviceIdentifier)
+
+	var resp CompatibilityResult
+	err := s.client.makeRequest(ctx, "GET", endpoint, nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check compatibility: %w", err)
+	}
+	if resp.Device == "" {
+		resp.Device = deviceIdentifier
+	}
+	return &resp, nil
+}
+
+// networkGenerations lists the network generations from oldest to newest
+var networkGenerations = []string{"2G", "3G", "4G", "5G"}
+
+// DeviceCapabilities describes the radio capabilities a device advertises
+type DeviceCapabilities struct {
+	// ESIM reports whether the device supports eSIM profiles
+	ESIM bool
+	// Speeds lists the supported technologies, such as "4G", "LTE" or "5G"
+	Speeds []string
+	// Bands lists the supported bands, such as "B20" (LTE) or "n78" (5G NR)
+	Bands []string
+}
+
+// generations returns the network generations the device supports
+func (d DeviceCapabilities) generations() map[string]bool {
+	supported := make(map[string]bool)
+	for _, speed := range d.Speeds {
+		if generation := normaliseSpeed(speed); generation != "" {
+			supported[generation] = true
+		}
+	}
+	for _, band := range d.Bands {
+		band = strings.TrimSpace(band)
+		switch {
+		case strings.HasPrefix(band, "n") || strings.HasPrefix(band, "N"):
+			supported["5G"] = true
+		case strings.HasPrefix(band, "B") || strings.HasPrefix(band, "b"):
+			supported["4G"] = true
+		}
+	}
+	return supported
+}
+
+// normaliseSpeed maps technology names to network generations
+func normaliseSpeed(speed string) string {
+	speed = strings.ToUpper(strings.TrimSpace(speed))
+	switch {
+	case strings.HasPrefix(speed, "5G") || speed == "NR":
+		return "5G"
+	case speed == "4G" || strings.HasPrefix(speed, "LTE"):
+		return "4G"
+	case speed == "3G" || speed == "UMTS" || strings.HasPrefix(speed, "HSPA") || speed == "WCDMA":
+		return "3G"
+	case speed == "2G" || speed == "GSM" || speed == "GPRS" || speed == "EDGE":
+		return "2G"
+	}
+	return ""
+}
+
+// NetworkSupport describes whether a device can use a network
+type NetworkSupport struct {
+	Network   Network
+	Supported bool
+	// CommonSpeeds lists the generations both the device and network support
+	CommonSpeeds []string
+	// BestSpeed is the newest generation in CommonSpeeds
+	BestSpeed string
+	Reasons   []string
+}
+
+// CheckNetworkSupport checks, without calling the API, whether a device's
+// advertised speeds and bands match the Speed list of a network returned by
+// NetworksService
+func CheckNetworkSupport(device DeviceCapabilities, network Network) NetworkSupport {
+	support := NetworkSupport{Network: network}
+
+	supported := device.generations()
+	offered := make(map[string]bool)
+	for _, speed := range network.Speed {
+		if generation := normaliseSpeed(speed); generation != "" {
+			offered[generation] = true
+		}
+	}
+
+	for _, generation := range networkGenerations {
+		if supported[generation] && offered[generation] {
+			support.CommonSpeeds = append(support.CommonSpeeds, generation)
+			support.BestSpeed = generation
+		}
+	}
+
+	if !device.ESIM {
+		support.Reasons = append(support.Reasons, "device does not support eSIM")
+	}
+	if len(support.CommonSpeeds) == 0 {
+		support.Reasons = append(support.Reasons,
+			fmt.Sprintf("no common speed with %s (network offers %s)", network.Name, strings.Join(network.Speed, ", ")))
+	}
+	support.Supported = len(support.Reasons) == 0
+	return support
+}
+
+// CheckNetworksSupport checks a device against every network of a networks
+// response and returns the support of each. A nil response yields nil.
+func CheckNetworksSupport(device DeviceCapabilities, networks *NetworksResponse) []NetworkSupport {
+	if networks == nil {
+		return nil
+	}
+	var results []NetworkSupport
+	for _, country := range networks.CountryNetworks {
+		for _, network := range country.Networks {
+			results = append(results, CheckNetworkSupport(device, network))
+		}
+	}
+	return results
+}
@@ -0,0 +1,101 @@
+package esimgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/esims/8944500102198304826/compatible/iPhone%2015":
+			w.Write([]byte(`{"compatible":true}`))
+		case "/esims/8944500102198304826/compatible/35391805":
+			w.Write([]byte(`{"compatible":false,"device":"Galaxy S9","reasons":["device does not support eSIM"]}`))
+		case "/esims/8944500102198304826/compatible/unknown-phone":
+			w.Write([]byte(`{"message":"device not in database"}`))
+		default:
+			t.Errorf("Unexpected path '%s'", r.URL.EscapedPath())
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	tests := []struct {
+		device  string
+		verdict CompatibilityVerdict
+		name    string
+	}{
+		{"iPhone 15", CompatibilityCompatible, "iPhone 15"},
+		{"35391805", CompatibilityIncompatible, "Galaxy S9"},
+		{"unknown-phone", CompatibilityUnknown, "unknown-phone"},
+	}
+	for _, tt := range tests {
+		result, err := client.ESIMs.CheckCompatibility(ctx, "8944500102198304826", tt.device)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.Verdict() != tt.verdict || result.Device != tt.name {
+			t.Errorf("CheckCompatibility(%q) = %s for %q, expected %s for %q", tt.device, result.Verdict(), result.Device, tt.verdict, tt.name)
+		}
+	}
+
+	if _, err := client.ESIMs.CheckCompatibility(ctx, "8944500102198304826", " "); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation for an empty device, got %v", err)
+	}
+}
+
+func TestCheckNetworkSupport(t *testing.T) {
+	vodafone := Network{Name: "Vodafone", Speed: []string{"2G", "3G", "4G", "5G"}}
+	legacy := Network{Name: "Legacy", Speed: []string{"2G", "3G"}}
+
+	tests := []struct {
+		name      string
+		device    DeviceCapabilities
+		network   Network
+		supported bool
+		common    string
+		best      string
+	}{
+		{"speeds", DeviceCapabilities{ESIM: true, Speeds: []string{"LTE", "3G"}}, vodafone, true, "3G,4G", "4G"},
+		{"bands", DeviceCapabilities{ESIM: true, Bands: []string{"B20", "n78"}}, vodafone, true, "4G,5G", "5G"},
+		{"5G NSA", DeviceCapabilities{ESIM: true, Speeds: []string{"5G NSA"}}, vodafone, true, "5G", "5G"},
+		{"no common speed", DeviceCapabilities{ESIM: true, Speeds: []string{"4G"}, Bands: []string{"n78"}}, legacy, false, "", ""},
+		{"no eSIM", DeviceCapabilities{Speeds: []string{"4G"}}, vodafone, false, "4G", "4G"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			support := CheckNetworkSupport(tt.device, tt.network)
+			if support.Supported != tt.supported {
+				t.Errorf("Expected supported %t, got %t (%v)", tt.supported, support.Supported, support.Reasons)
+			}
+			if got := strings.Join(support.CommonSpeeds, ","); got != tt.common {
+				t.Errorf("Expected common speeds '%s', got '%s'", tt.common, got)
+			}
+			if support.BestSpeed != tt.best {
+				t.Errorf("Expected best speed '%s', got '%s'", tt.best, support.BestSpeed)
+			}
+			if !tt.supported && len(support.Reasons) == 0 {
+				t.Error("Expected a reason for the lack of support")
+			}
+		})
+	}
+
+	results := CheckNetworksSupport(DeviceCapabilities{ESIM: true, Speeds: []string{"4G"}}, &NetworksResponse{
+		CountryNetworks: []CountryNetwork{{Name: "Spain", Networks: []Network{vodafone, legacy}}},
+	})
+	if len(results) != 2 || !results[0].Supported || results[1].Supported {
+		t.Errorf("Unexpected results %+v", results)
+	}
+	if results := CheckNetworksSupport(DeviceCapabilities{ESIM: true}, nil); results != nil {
+		t.Errorf("Expected no results for a nil response, got %+v", results)
+	}
+}
@@ -0,0 +1,140 @@
+package esimgo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sentinel errors matched by APIError through errors.Is
+var (
+	// ErrNotFound reports that the requested resource, such as an ICCID,
+	// does not exist
+	ErrNotFound = errors.New("esimgo: not found")
+	// ErrUnauthorized reports a missing, invalid or insufficiently
+	// privileged API key
+	ErrUnauthorized = errors.New("esimgo: unauthorized")
+	// ErrInsufficientBalance reports that the organisation does not have
+	// enough credit for the operation
+	ErrInsufficientBalance = errors.New("esimgo: insufficient balance")
+	// ErrRateLimited reports that the API rejected the request because too
+	// many requests were sent
+	ErrRateLimited = errors.New("esimgo: rate limited")
+	// ErrValidation reports that the request parameters were rejected,
+	// either by the API or by client-side validation
+	ErrValidation = errors.New("esimgo: validation failed")
+	// ErrProfileNotInstalled reports that the operation needs the eSIM
+	// profile to be installed on a device
+	ErrProfileNotInstalled = errors.New("esimgo: profile not installed")
+)
+
+// APIError represents an API error response
+type APIError struct {
+	Message    string `json:"message"`
+	StatusCode int    `json:"-"`
+	// RequestID is the identifier the API assigned to the request, if any
+	RequestID string `json:"-"`
+	// Method and Endpoint identify the request that failed
+	Method   string `json:"-"`
+	Endpoint string `json:"-"`
+	// RawBody is the unparsed response body
+	RawBody []byte `json:"-"`
+	// RetryAfter is the delay requested by the API before trying again
+	RetryAfter time.Duration `json:"-"`
+}
+
+func (e *APIError) Error() string {
+	if e.Method == "" && e.Endpoint == "" {
+		return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("API error %d on %s %s: %s", e.StatusCode, e.Method, e.Endpoint, e.Message)
+}
+
+// Is reports whether the error matches one of the package sentinel errors
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.isNotFound()
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrInsufficientBalance:
+		return e.isInsufficientBalance()
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrProfileNotInstalled:
+		return e.isProfileNotInstalled()
+	case ErrValidation:
+		// Bad requests that have a more specific meaning are not reported
+		// as validation errors
+		return (e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity) &&
+			!e.isNotFound() && !e.isInsufficientBalance() && !e.isProfileNotInstalled()
+	}
+	return false
+}
+
+func (e *APIError) isNotFound() bool {
+	if e.StatusCode == http.StatusNotFound {
+		return true
+	}
+	return e.StatusCode < 500 && strings.Contains(strings.ToLower(e.Message), "not found")
+}
+
+func (e *APIError) isInsufficientBalance() bool {
+	if e.StatusCode == http.StatusPaymentRequired {
+		return true
+	}
+	if e.StatusCode >= 500 {
+		return false
+	}
+	message := strings.ToLower(e.Message)
+	if strings.Contains(message, "out of credit") {
+		return true
+	}
+	return strings.Contains(message, "insufficient") &&
+		(strings.Contains(message, "balance") || strings.Contains(message, "credit") || strings.Contains(message, "funds"))
+}
+
+func (e *APIError) isProfileNotInstalled() bool {
+	if e.StatusCode < 400 || e.StatusCode >= 500 {
+		return false
+	}
+	message := strings.ToLower(e.Message)
+	return strings.Contains(message, "not installed") || strings.Contains(message, "not been installed")
+}
+
+// newAPIError builds an APIError from an error response, whether or not its
+// body is JSON
+func newAPIError(method, endpoint string, resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  requestID(resp.Header),
+		Method:     method,
+		Endpoint:   endpoint,
+		RawBody:    body,
+	}
+
+	if err := json.Unmarshal(body, apiErr); err != nil {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	if wait, ok := parseRetryAfter(resp.Header, time.Now()); ok {
+		apiErr.RetryAfter = wait
+	}
+
+	return apiErr
+}
+
+// requestID returns the request identifier sent back by the API
+func requestID(header http.Header) string {
+	for _, key := range []string{"X-Request-Id", "X-Amzn-Requestid", "X-Correlation-Id"} {
+		if id := header.Get(key); id != "" {
+			return id
+		}
+	}
+	return ""
+}
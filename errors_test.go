@@ -0,0 +1,104 @@
+package esimgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAPIErrors(t *testing.T) {
+	sentinels := []error{ErrNotFound, ErrUnauthorized, ErrInsufficientBalance, ErrRateLimited, ErrValidation, ErrProfileNotInstalled}
+
+	tests := []struct {
+		name        string
+		statusCode  int
+		body        string
+		header      http.Header
+		want        error
+		wantMessage string
+	}{
+		{"not found status", http.StatusNotFound, `{"message":"eSIM not found"}`, nil, ErrNotFound, "eSIM not found"},
+		{"ICCID not found on bad request", http.StatusBadRequest, `{"message":"ICCID not found"}`, nil, ErrNotFound, "ICCID not found"},
+		{"unauthorized", http.StatusUnauthorized, `{"message":"Invalid API key"}`, nil, ErrUnauthorized, "Invalid API key"},
+		{"forbidden", http.StatusForbidden, `{"message":"Forbidden"}`, nil, ErrUnauthorized, "Forbidden"},
+		{"insufficient balance", http.StatusBadRequest, `{"message":"Insufficient balance to complete order"}`, nil, ErrInsufficientBalance, "Insufficient balance to complete order"},
+		{"out of credit", http.StatusBadRequest, `{"message":"Organisation is out of credit"}`, nil, ErrInsufficientBalance, "Organisation is out of credit"},
+		{"payment required", http.StatusPaymentRequired, `{}`, nil, ErrInsufficientBalance, "Payment Required"},
+		{"rate limited", http.StatusTooManyRequests, `Too Many Requests`, http.Header{"Retry-After": []string{"4"}}, ErrRateLimited, "Too Many Requests"},
+		{"profile not installed", http.StatusBadRequest, `{"message":"Profile not installed"}`, nil, ErrProfileNotInstalled, "Profile not installed"},
+		{"validation", http.StatusBadRequest, `{"message":"Invalid bundle name"}`, nil, ErrValidation, "Invalid bundle name"},
+		{"unprocessable", http.StatusUnprocessableEntity, `{"message":"quantity must be positive"}`, nil, ErrValidation, "quantity must be positive"},
+		{"non-JSON server error", http.StatusBadGateway, "<html>Bad Gateway</html>", nil, nil, "<html>Bad Gateway</html>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-Id", "req-123")
+				for key, values := range tt.header {
+					w.Header()[key] = values
+				}
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+			_, err := client.ESIMs.GetDetails(context.Background(), "8944500102198304826", "")
+			if err == nil {
+				t.Fatal("Expected an error, got nil")
+			}
+
+			for _, sentinel := range sentinels {
+				if got := errors.Is(err, sentinel); got != (sentinel == tt.want) {
+					t.Errorf("errors.Is(err, %v) = %t", sentinel, got)
+				}
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected an *APIError, got %T", err)
+			}
+			if apiErr.StatusCode != tt.statusCode {
+				t.Errorf("Expected status code %d, got %d", tt.statusCode, apiErr.StatusCode)
+			}
+			if apiErr.Message != tt.wantMessage {
+				t.Errorf("Expected message '%s', got '%s'", tt.wantMessage, apiErr.Message)
+			}
+			if apiErr.RequestID != "req-123" {
+				t.Errorf("Expected request ID 'req-123', got '%s'", apiErr.RequestID)
+			}
+			if apiErr.Method != "GET" || apiErr.Endpoint != "/esims/8944500102198304826" {
+				t.Errorf("Expected GET /esims/8944500102198304826, got %s %s", apiErr.Method, apiErr.Endpoint)
+			}
+			if string(apiErr.RawBody) != tt.body {
+				t.Errorf("Expected raw body '%s', got '%s'", tt.body, apiErr.RawBody)
+			}
+		})
+	}
+}
+
+func TestAPIErrorRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "4")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-api-key", WithBaseURL(server.URL))
+	err := client.makeRequest(context.Background(), "GET", "/catalogue", nil, nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an *APIError, got %T", err)
+	}
+	if apiErr.RetryAfter != 4*time.Second {
+		t.Errorf("Expected RetryAfter 4s, got %v", apiErr.RetryAfter)
+	}
+	if apiErr.Message != "Too Many Requests" {
+		t.Errorf("Expected the status text as message, got '%s'", apiErr.Message)
+	}
+}
@@ -2,14 +2,19 @@ package esimgo
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 )
 
 // ApplyBundleRequest represents a request to apply a bundle to an eSIM
 type ApplyBundleRequest struct {
 	Name          string `json:"name"`
-	ICCID         string `json:"iccid,omitempty"`
+	ICCID         ICCID  `json:"iccid,omitempty"`
 	Repeat        int    `json:"repeat,omitempty"`
 	AllowReassign bool   `json:"allowReassign,omitempty"`
 }
@@ -22,7 +27,7 @@ type ApplyBundleResponse struct {
 
 // ESIMBundle represents an eSIM with bundle information
 type ESIMBundle struct {
-	ICCID  string `json:"iccid"`
+	ICCID  ICCID  `json:"iccid"`
 	Status string `json:"status"`
 	Bundle string `json:"bundle"`
 }
@@ -48,8 +53,8 @@ func (s *ESIMService) ApplyBundle(ctx context.Context, req *ApplyBundleRequest)
 }
 
 // GetDetails retrieves details for a specific eSIM
-func (s *ESIMService) GetDetails(ctx context.Context, iccid string, additionalFields string) (*ESIM, error) {
-	endpoint := "/esims/" + iccid
+func (s *ESIMService) GetDetails(ctx context.Context, iccid ICCID, additionalFields string) (*ESIM, error) {
+	endpoint := "/esims/" + iccid.PathSegment()
 	if additionalFields != "" {
 		params := url.Values{}
 		params.Set("additionalFields", additionalFields)
@@ -64,5 +69,574 @@ func (s *ESIMService) GetDetails(ctx context.Context, iccid string, additionalFi
 	return &resp, nil
 }
 
-// Additional structures and methods would go here...
-// (This is a simplified version for the setup script)
+// ListESIMsRequest represents query parameters for listing eSIMs
+type ListESIMsRequest struct {
+	Page      int    `json:"page,omitempty"`
+	PerPage   int    `json:"perPage,omitempty"`
+	Direction string `json:"direction,omitempty"`
+	OrderBy   string `json:"orderBy,omitempty"`
+	// FilterBy names the field Filter applies to, such as FilterByICCID
+	FilterBy string `json:"filterBy,omitempty"`
+	Filter   string `json:"filter,omitempty"`
+}
+
+// Fields eSIMs can be filtered by
+const (
+	FilterByICCID         = "iccid"
+	FilterByCustomerRef   = "customerRef"
+	FilterByProfileStatus = "profileStatus"
+)
+
+// ESIMs represents a page of eSIMs
+type ESIMs struct {
+	ESIMs []ESIM `json:"esims"`
+	PageInfo
+}
+
+// List retrieves a page of the eSIMs owned by the organisation
+func (s *ESIMService) List(ctx context.Context, req *ListESIMsRequest) (*ESIMs, error) {
+	if req == nil {
+		req = &ListESIMsRequest{}
+	}
+
+	params := url.Values{}
+	if req.Page > 0 {
+		params.Set("page", strconv.Itoa(req.Page))
+	}
+	if req.PerPage > 0 {
+		params.Set("perPage", strconv.Itoa(req.PerPage))
+	}
+	if req.Direction != "" {
+		params.Set("direction", req.Direction)
+	}
+	if req.OrderBy != "" {
+		params.Set("orderBy", req.OrderBy)
+	}
+	if req.FilterBy != "" {
+		params.Set("filterBy", req.FilterBy)
+	}
+	if req.Filter != "" {
+		params.Set("filter", req.Filter)
+	}
+
+	endpoint := "/esims"
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	var resp ESIMs
+	err := s.client.makeRequest(ctx, "GET", endpoint, nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list eSIMs: %w", err)
+	}
+	return &resp, nil
+}
+
+// ListAll returns an iterator over every eSIM matching req, starting at
+// req.Page and fetching further pages lazily
+func (s *ESIMService) ListAll(ctx context.Context, req *ListESIMsRequest) *Iterator[ESIM] {
+	base := ListESIMsRequest{}
+	if req != nil {
+		base = *req
+	}
+
+	return newIterator(ctx, base.Page, func(ctx context.Context, page int) ([]ESIM, PageInfo, error) {
+		pageReq := base
+		pageReq.Page = page
+		resp, err := s.List(ctx, &pageReq)
+		if err != nil {
+			return nil, PageInfo{}, err
+		}
+		return resp.ESIMs, resp.PageInfo, nil
+	})
+}
+
+// AssignedBundle represents a bundle applied to an eSIM and its assignments
+type AssignedBundle struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Assignments []Assignment `json:"assignments"`
+}
+
+// AssignedBundles represents the bundles applied to an eSIM
+type AssignedBundles struct {
+	Bundles []AssignedBundle `json:"bundles"`
+}
+
+// usable reports whether an assignment still has data that can be consumed
+func (a *Assignment) usable() bool {
+	return a.BundleState.IsPending() || a.BundleState.IsActive()
+}
+
+// RemainingQuantity returns the data left across the assignments that are
+// still usable
+func (b *AssignedBundle) RemainingQuantity() int64 {
+	var remaining int64
+	for _, assignment := range b.Assignments {
+		if assignment.usable() {
+			remaining += assignment.RemainingQuantity
+		}
+	}
+	return remaining
+}
+
+// Unlimited reports whether any usable assignment is unlimited
+func (b *AssignedBundle) Unlimited() bool {
+	for _, assignment := range b.Assignments {
+		if assignment.usable() && assignment.Unlimited {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveAssignment returns the assignment currently being consumed, if any
+func (b *AssignedBundle) ActiveAssignment() (*Assignment, bool) {
+	for i := range b.Assignments {
+		if b.Assignments[i].BundleState.IsActive() {
+			return &b.Assignments[i], true
+		}
+	}
+	return nil, false
+}
+
+// ListBundles retrieves the bundles applied to an eSIM. Depleted, expired
+// and revoked bundles are only included when includeUsed is true.
+func (s *ESIMService) ListBundles(ctx context.Context, iccid ICCID, includeUsed bool) (*AssignedBundles, error) {
+	endpoint := "/esims/" + iccid.PathSegment() + "/bundles"
+	if includeUsed {
+		params := url.Values{}
+		params.Set("includeUsed", "true")
+		endpoint += "?" + params.Encode()
+	}
+
+	var resp AssignedBundles
+	err := s.client.makeRequest(ctx, "GET", endpoint, nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list eSIM bundles: %w", err)
+	}
+	return &resp, nil
+}
+
+// GetBundle retrieves a single bundle applied to an eSIM, including the
+// remaining quantity of each assignment
+func (s *ESIMService) GetBundle(ctx context.Context, iccid ICCID, bundleName string) (*AssignedBundle, error) {
+	endpoint := "/esims/" + iccid.PathSegment() + "/bundles/" + url.PathEscape(bundleName)
+
+	var resp AssignedBundle
+	err := s.client.makeRequest(ctx, "GET", endpoint, nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get eSIM bundle: %w", err)
+	}
+	return &resp, nil
+}
+
+// RevokeBundleOptions represents options for revoking a bundle
+type RevokeBundleOptions struct {
+	// RefundToBalance credits the bundle price to the organisation balance
+	// instead of returning the bundle to inventory
+	RefundToBalance bool
+	// AssignmentID revokes a single assignment of the bundle
+	AssignmentID string
+}
+
+// RefundDestination describes where the credit of a revoked bundle went
+type RefundDestination string
+
+// Refund destinations
+const (
+	RefundDestinationInventory RefundDestination = "inventory"
+	RefundDestinationBalance   RefundDestination = "balance"
+	// RefundDestinationUnknown is reported when the API did not say where
+	// the credit went
+	RefundDestinationUnknown RefundDestination = "unknown"
+)
+
+// RevokeBundleResult represents the outcome of revoking a bundle
+type RevokeBundleResult struct {
+	ICCID        ICCID             `json:"iccid"`
+	Bundle       string            `json:"bundle"`
+	AssignmentID string            `json:"assignmentId,omitempty"`
+	RefundedTo   RefundDestination `json:"refundedTo"`
+	Message      string            `json:"message,omitempty"`
+}
+
+// RevokeBundle revokes a bundle applied to an eSIM, returning its credit to
+// inventory or, with RefundToBalance, to the organisation balance. The
+// result reports RefundDestinationUnknown unless the API confirmed where the
+// credit went.
+func (s *ESIMService) RevokeBundle(ctx context.Context, iccid ICCID, bundleName string, opts *RevokeBundleOptions) (*RevokeBundleResult, error) {
+	if opts == nil {
+		opts = &RevokeBundleOptions{}
+	}
+
+	endpoint := "/esims/" + iccid.PathSegment() + "/bundles/" + url.PathEscape(bundleName)
+	if opts.AssignmentID != "" {
+		endpoint += "/assignments/" + url.PathEscape(opts.AssignmentID)
+	}
+	if opts.RefundToBalance {
+		params := url.Values{}
+		params.Set("refundToBalance", "true")
+		endpoint += "?" + params.Encode()
+	}
+
+	var resp RevokeBundleResult
+	err := s.client.makeRequest(ctx, "DELETE", endpoint, nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke bundle: %w", err)
+	}
+
+	resp.ICCID = iccid
+	resp.Bundle = bundleName
+	resp.AssignmentID = opts.AssignmentID
+	if resp.RefundedTo == "" {
+		resp.RefundedTo = RefundDestinationUnknown
+	}
+	return &resp, nil
+}
+
+// ESIMLocation represents the network an eSIM last attached to
+type ESIMLocation struct {
+	// MobileNetworkCode is the MCC followed by the MNC, e.g. "23410"
+	MobileNetworkCode string    `json:"mobileNetworkCode"`
+	NetworkName       string    `json:"networkName"`
+	Country           string    `json:"country"`
+	LastSeen          Timestamp `json:"lastSeen"`
+}
+
+// MCC returns the mobile country code of the network
+func (l *ESIMLocation) MCC() string {
+	if len(l.MobileNetworkCode) < 3 {
+		return l.MobileNetworkCode
+	}
+	return l.MobileNetworkCode[:3]
+}
+
+// MNC returns the mobile network code of the network
+func (l *ESIMLocation) MNC() string {
+	if len(l.MobileNetworkCode) < 3 {
+		return ""
+	}
+	return l.MobileNetworkCode[3:]
+}
+
+// ResolvedLocation represents an eSIM location cross-referenced with the
+// network data of NetworksService
+type ResolvedLocation struct {
+	ESIMLocation
+	// CountryName is the name of the country the network belongs to
+	CountryName string
+	// Network is nil when the network is not in the networks data
+	Network *Network
+}
+
+// GetLocation retrieves the country and network an eSIM last attached to
+func (s *ESIMService) GetLocation(ctx context.Context, iccid ICCID) (*ESIMLocation, error) {
+	endpoint := "/esims/" + iccid.PathSegment() + "/location"
+
+	var resp ESIMLocation
+	err := s.client.makeRequest(ctx, "GET", endpoint, nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get eSIM location: %w", err)
+	}
+	return &resp, nil
+}
+
+// ResolveLocation retrieves the location of an eSIM and resolves its MCC
+// and MNC into the matching Network, with brand name and speeds
+func (s *ESIMService) ResolveLocation(ctx context.Context, iccid ICCID) (*ResolvedLocation, error) {
+	location, err := s.GetLocation(ctx, iccid)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := &ResolvedLocation{ESIMLocation: *location}
+	if location.Country == "" || location.MobileNetworkCode == "" {
+		return resolved, nil
+	}
+
+	req := &GetNetworksRequest{}
+	if len(location.Country) == 2 {
+		req.ISOs = []string{location.Country}
+	} else {
+		req.Countries = []string{location.Country}
+	}
+	networks, err := NewNetworksService(s.client).GetCountryNetworks(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve eSIM location: %w", err)
+	}
+
+	if network, country, ok := networks.FindNetwork(location.MCC(), location.MNC()); ok {
+		resolved.Network = network
+		resolved.CountryName = country.Name
+	}
+	return resolved, nil
+}
+
+// ESIMEventType classifies the actions recorded in an eSIM history
+type ESIMEventType string
+
+// eSIM history event types
+const (
+	ESIMEventInstalled      ESIMEventType = "installed"
+	ESIMEventEnabled        ESIMEventType = "enabled"
+	ESIMEventDisabled       ESIMEventType = "disabled"
+	ESIMEventBundleApplied  ESIMEventType = "bundle_applied"
+	ESIMEventBundleDepleted ESIMEventType = "bundle_depleted"
+	ESIMEventOther          ESIMEventType = "other"
+)
+
+// classifyESIMEvent maps the action names used by the API to event types.
+// Whole words are matched, so "Uninstalled" is not taken for "Installed",
+// and negated or failed actions are classified as ESIMEventOther.
+func classifyESIMEvent(name string) ESIMEventType {
+	has := wordMatcher(name)
+	switch {
+	case has("not", "failed", "failure"):
+		return ESIMEventOther
+	case has("deplete", "depleted", "depletion"):
+		return ESIMEventBundleDepleted
+	case has("bundle") && has("apply", "applied", "assign", "assigned"):
+		return ESIMEventBundleApplied
+	case has("install", "installed", "installation"):
+		return ESIMEventInstalled
+	case has("disable", "disabled"):
+		return ESIMEventDisabled
+	case has("enable", "enabled"):
+		return ESIMEventEnabled
+	default:
+		return ESIMEventOther
+	}
+}
+
+// wordMatcher splits text into lower case words and returns a function
+// reporting whether any of the candidates is one of them
+func wordMatcher(text string) func(candidates ...string) bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		words[word] = true
+	}
+	return func(candidates ...string) bool {
+		for _, candidate := range candidates {
+			if words[candidate] {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ESIMEvent represents an action in the history of an eSIM
+type ESIMEvent struct {
+	// Type is derived from Name when the event is decoded
+	Type       ESIMEventType `json:"type"`
+	Name       string        `json:"name"`
+	BundleName string        `json:"bundleName,omitempty"`
+	Alias      string        `json:"alias,omitempty"`
+	Date       Timestamp     `json:"date"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, classifying the event
+func (e *ESIMEvent) UnmarshalJSON(data []byte) error {
+	type event ESIMEvent
+	var decoded event
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*e = ESIMEvent(decoded)
+	e.Type = classifyESIMEvent(e.Name)
+	return nil
+}
+
+// ESIMHistory represents a page of the history of an eSIM
+type ESIMHistory struct {
+	ICCID   ICCID       `json:"iccid"`
+	Actions []ESIMEvent `json:"actions"`
+	PageInfo
+}
+
+// HistoryRequest represents query parameters for retrieving eSIM history
+type HistoryRequest struct {
+	Page    int `json:"page,omitempty"`
+	PerPage int `json:"perPage,omitempty"`
+	// From and To restrict the events to a time range; zero values leave
+	// the range open
+	From time.Time `json:"from,omitempty"`
+	To   time.Time `json:"to,omitempty"`
+}
+
+// includes reports whether an event falls in the requested time range
+func (r *HistoryRequest) includes(event ESIMEvent) bool {
+	if !r.From.IsZero() && event.Date.Before(r.From) {
+		return false
+	}
+	if !r.To.IsZero() && event.Date.After(r.To) {
+		return false
+	}
+	return true
+}
+
+// History retrieves a page of the actions performed on an eSIM. Events
+// outside the requested time range are filtered out even when the API
+// returns them.
+func (s *ESIMService) History(ctx context.Context, iccid ICCID, req *HistoryRequest) (*ESIMHistory, error) {
+	if req == nil {
+		req = &HistoryRequest{}
+	}
+	resp, err := s.history(ctx, iccid, req)
+	if err != nil {
+		return nil, err
+	}
+
+	events := resp.Actions[:0]
+	for _, event := range resp.Actions {
+		if req.includes(event) {
+			events = append(events, event)
+		}
+	}
+	resp.Actions = events
+	return resp, nil
+}
+
+// history retrieves a page of the history of an eSIM as returned by the API
+func (s *ESIMService) history(ctx context.Context, iccid ICCID, req *HistoryRequest) (*ESIMHistory, error) {
+	params := url.Values{}
+	if req.Page > 0 {
+		params.Set("page", strconv.Itoa(req.Page))
+	}
+	if req.PerPage > 0 {
+		params.Set("perPage", strconv.Itoa(req.PerPage))
+	}
+	if !req.From.IsZero() {
+		params.Set("from", req.From.UTC().Format(time.RFC3339))
+	}
+	if !req.To.IsZero() {
+		params.Set("to", req.To.UTC().Format(time.RFC3339))
+	}
+
+	endpoint := "/esims/" + iccid.PathSegment() + "/history"
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	var resp ESIMHistory
+	err := s.client.makeRequest(ctx, "GET", endpoint, nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get eSIM history: %w", err)
+	}
+	return &resp, nil
+}
+
+// HistoryAll returns an iterator over every event in the history of an
+// eSIM, starting at req.Page and fetching further pages lazily. Events
+// outside the requested time range are skipped.
+func (s *ESIMService) HistoryAll(ctx context.Context, iccid ICCID, req *HistoryRequest) *Iterator[ESIMEvent] {
+	base := HistoryRequest{}
+	if req != nil {
+		base = *req
+	}
+
+	return newIterator(ctx, base.Page, func(ctx context.Context, page int) ([]ESIMEvent, PageInfo, error) {
+		pageReq := base
+		pageReq.Page = page
+		resp, err := s.history(ctx, iccid, &pageReq)
+		if err != nil {
+			return nil, PageInfo{}, err
+		}
+		return resp.Actions, resp.PageInfo, nil
+	}).filter(base.includes)
+}
+
+// ESIMUpdate represents the mutable attributes of an eSIM; nil fields are
+// left unchanged
+type ESIMUpdate struct {
+	CustomerRef *string `json:"customerRef,omitempty"`
+}
+
+// updateESIMRequest represents the body of an eSIM update
+type updateESIMRequest struct {
+	ICCID ICCID `json:"iccid"`
+	ESIMUpdate
+}
+
+// ESIMUpdateItem pairs an ICCID with the update to apply to it
+type ESIMUpdateItem struct {
+	ICCID  ICCID
+	Update ESIMUpdate
+}
+
+// ESIMUpdateResult represents the outcome of updating a single eSIM
+type ESIMUpdateResult struct {
+	ICCID ICCID
+	ESIM  *ESIM
+	Err   error
+}
+
+// BulkUpdateResult represents the outcome of updating many eSIMs
+type BulkUpdateResult struct {
+	Results []ESIMUpdateResult
+}
+
+// Succeeded returns the ICCIDs that were updated
+func (r *BulkUpdateResult) Succeeded() []ICCID {
+	var iccids []ICCID
+	for _, result := range r.Results {
+		if result.Err == nil {
+			iccids = append(iccids, result.ICCID)
+		}
+	}
+	return iccids
+}
+
+// Failed returns the results of the updates that failed
+func (r *BulkUpdateResult) Failed() []ESIMUpdateResult {
+	var failed []ESIMUpdateResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Update changes the mutable attributes of an eSIM, such as its customer
+// reference. When the API does not return the eSIM, only the ICCID and the
+// updated fields of the result are set.
+func (s *ESIMService) Update(ctx context.Context, iccid ICCID, patch ESIMUpdate) (*ESIM, error) {
+	req := &updateESIMRequest{
+		ICCID:      iccid,
+		ESIMUpdate: patch,
+	}
+
+	var resp ESIM
+	err := s.client.makeRequest(ctx, "PUT", "/esims", req, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update eSIM: %w", err)
+	}
+	if resp.ICCID == "" {
+		// The API did not echo the eSIM back
+		resp.ICCID = iccid
+		if patch.CustomerRef != nil {
+			resp.CustomerRef = *patch.CustomerRef
+		}
+	}
+	return &resp, nil
+}
+
+// UpdateMany applies updates to many eSIMs, one request per ICCID, and
+// reports the outcome of each. A failed update does not stop the others;
+// once ctx is done the remaining updates fail with the context error.
+func (s *ESIMService) UpdateMany(ctx context.Context, items []ESIMUpdateItem) *BulkUpdateResult {
+	result := &BulkUpdateResult{Results: make([]ESIMUpdateResult, len(items))}
+	for i, item := range items {
+		result.Results[i].ICCID = item.ICCID
+		if err := ctx.Err(); err != nil {
+			result.Results[i].Err = err
+			continue
+		}
+		result.Results[i].ESIM, result.Results[i].Err = s.Update(ctx, item.ICCID, item.Update)
+	}
+	return result
+}
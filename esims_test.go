@@ -0,0 +1,477 @@
+package esimgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestESIMListQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		req   *ListESIMsRequest
+		query string
+	}{
+		{"nil request", nil, ""},
+		{"pagination", &ListESIMsRequest{Page: 3, PerPage: 25, Direction: DirectionAsc, OrderBy: "assignedDate"}, "direction=asc&orderBy=assignedDate&page=3&perPage=25"},
+		{"filter by customer reference", &ListESIMsRequest{FilterBy: FilterByCustomerRef, Filter: "cust 42"}, "filter=cust+42&filterBy=customerRef"},
+		{"filter by profile status", &ListESIMsRequest{FilterBy: FilterByProfileStatus, Filter: "Installed"}, "filter=Installed&filterBy=profileStatus"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "GET" || r.URL.Path != "/esims" {
+					t.Errorf("Expected GET /esims, got %s %s", r.Method, r.URL.Path)
+				}
+				gotQuery = r.URL.RawQuery
+				w.Write([]byte(`{"esims":[{"iccid":"8944500102198304826","customerRef":"cust 42"}],"pageCount":1,"rows":1}`))
+			}))
+			defer server.Close()
+
+			client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+			resp, err := client.ESIMs.List(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if gotQuery != tt.query {
+				t.Errorf("Expected query '%s', got '%s'", tt.query, gotQuery)
+			}
+			if len(resp.ESIMs) != 1 || resp.ESIMs[0].CustomerRef != "cust 42" {
+				t.Errorf("Unexpected eSIMs %+v", resp.ESIMs)
+			}
+			if resp.PageCount != 1 || resp.Rows != 1 {
+				t.Errorf("Expected page count 1 and 1 row, got %+v", resp.PageInfo)
+			}
+		})
+	}
+}
+
+func TestESIMListAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("filterBy"); got != FilterByProfileStatus {
+			t.Errorf("Expected filterBy on every page, got '%s'", got)
+		}
+
+		resp := ESIMs{PageInfo: PageInfo{PageCount: 2, Rows: 3}}
+		switch r.URL.Query().Get("page") {
+		case "1":
+			resp.ESIMs = []ESIM{{ICCID: "8944500102198304826"}, {ICCID: "8944500102198304834"}}
+		case "2":
+			resp.ESIMs = []ESIM{{ICCID: "8944500102198304842"}}
+		default:
+			t.Errorf("Unexpected page '%s'", r.URL.Query().Get("page"))
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+	esims, err := client.ESIMs.ListAll(context.Background(), &ListESIMsRequest{
+		PerPage:  2,
+		FilterBy: FilterByProfileStatus,
+		Filter:   "Installed",
+	}).Collect()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(esims) != 3 {
+		t.Fatalf("Expected 3 eSIMs, got %d", len(esims))
+	}
+	if esims[2].ICCID != "8944500102198304842" {
+		t.Errorf("Expected last ICCID '8944500102198304842', got '%s'", esims[2].ICCID)
+	}
+}
+
+const assignedBundlesPayload = `{
+  "bundles": [
+    {
+      "name": "esim_1GB_7D_ES_V2",
+      "description": "eSIM, 1GB, 7 Days, Spain, V2",
+      "assignments": [
+        {
+          "id": "12345",
+          "callTypeGroup": "data",
+          "initialQuantity": 1000000000,
+          "remainingQuantity": 250000000,
+          "assignmentDateTime": "2024-03-01T10:00:00Z",
+          "assignmentReference": "ref-1",
+          "bundleState": "Active",
+          "unlimited": false
+        },
+        {
+          "id": "12346",
+          "callTypeGroup": "data",
+          "initialQuantity": 1000000000,
+          "remainingQuantity": 1000000000,
+          "assignmentDateTime": "2024-03-02T10:00:00Z",
+          "assignmentReference": "ref-2",
+          "bundleState": "Queued",
+          "unlimited": false
+        },
+        {
+          "id": "12340",
+          "callTypeGroup": "data",
+          "initialQuantity": 1000000000,
+          "remainingQuantity": 0,
+          "assignmentDateTime": "2024-02-01T10:00:00Z",
+          "assignmentReference": "ref-0",
+          "bundleState": "Depleted",
+          "unlimited": false
+        }
+      ]
+    }
+  ]
+}`
+
+func TestESIMListBundles(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/esims/8944500102198304826/bundles" {
+			t.Errorf("Expected path '/esims/8944500102198304826/bundles', got '%s'", r.URL.Path)
+		}
+		gotQuery = r.URL.RawQuery
+		w.Write([]byte(assignedBundlesPayload))
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+
+	resp, err := client.ESIMs.ListBundles(context.Background(), "8944500102198304826", true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if gotQuery != "includeUsed=true" {
+		t.Errorf("Expected query 'includeUsed=true', got '%s'", gotQuery)
+	}
+	if len(resp.Bundles) != 1 || len(resp.Bundles[0].Assignments) != 3 {
+		t.Fatalf("Unexpected bundles %+v", resp.Bundles)
+	}
+
+	bundle := resp.Bundles[0]
+	if got := bundle.RemainingQuantity(); got != 1250000000 {
+		t.Errorf("Expected 1250000000 remaining, got %d", got)
+	}
+	active, ok := bundle.ActiveAssignment()
+	if !ok || active.ID != "12345" {
+		t.Errorf("Expected active assignment '12345', got %+v", active)
+	}
+	if bundle.Unlimited() {
+		t.Error("Expected a limited bundle")
+	}
+
+	if _, err := client.ESIMs.ListBundles(context.Background(), "8944500102198304826", false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if gotQuery != "" {
+		t.Errorf("Expected no query, got '%s'", gotQuery)
+	}
+}
+
+func TestESIMGetBundle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/esims/8944500102198304826/bundles/esim_1GB_7D_ES_V2" {
+			t.Errorf("Unexpected path '%s'", r.URL.EscapedPath())
+		}
+		w.Write([]byte(`{"name":"esim_1GB_7D_ES_V2","assignments":[{"id":"1","remainingQuantity":42,"bundleState":"Active"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+
+	bundle, err := client.ESIMs.GetBundle(context.Background(), "8944500102198304826", "esim_1GB_7D_ES_V2")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if bundle.Name != "esim_1GB_7D_ES_V2" || bundle.RemainingQuantity() != 42 {
+		t.Errorf("Unexpected bundle %+v", bundle)
+	}
+}
+
+func TestESIMRevokeBundle(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       *RevokeBundleOptions
+		path       string
+		query      string
+		body       string
+		wantRefund RefundDestination
+	}{
+		{"to inventory", nil, "/esims/8944500102198304826/bundles/esim_1GB_7D_ES_V2", "", `{"refundedTo":"inventory"}`, RefundDestinationInventory},
+		{"to balance", &RevokeBundleOptions{RefundToBalance: true}, "/esims/8944500102198304826/bundles/esim_1GB_7D_ES_V2", "refundToBalance=true", `{"message":"Bundle revoked","refundedTo":"balance"}`, RefundDestinationBalance},
+		{"unconfirmed refund", &RevokeBundleOptions{RefundToBalance: true}, "/esims/8944500102198304826/bundles/esim_1GB_7D_ES_V2", "refundToBalance=true", `{"message":"Bundle revoked"}`, RefundDestinationUnknown},
+		{"single assignment", &RevokeBundleOptions{AssignmentID: "12345"}, "/esims/8944500102198304826/bundles/esim_1GB_7D_ES_V2/assignments/12345", "", "", RefundDestinationUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "DELETE" {
+					t.Errorf("Expected DELETE, got %s", r.Method)
+				}
+				if r.URL.Path != tt.path {
+					t.Errorf("Expected path '%s', got '%s'", tt.path, r.URL.Path)
+				}
+				if r.URL.RawQuery != tt.query {
+					t.Errorf("Expected query '%s', got '%s'", tt.query, r.URL.RawQuery)
+				}
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+			result, err := client.ESIMs.RevokeBundle(context.Background(), "8944500102198304826", "esim_1GB_7D_ES_V2", tt.opts)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result.RefundedTo != tt.wantRefund {
+				t.Errorf("Expected refund to '%s', got '%s'", tt.wantRefund, result.RefundedTo)
+			}
+			if result.ICCID != "8944500102198304826" || result.Bundle != "esim_1GB_7D_ES_V2" {
+				t.Errorf("Unexpected result %+v", result)
+			}
+		})
+	}
+}
+
+func TestESIMResolveLocation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/esims/8944500102198304826/location":
+			w.Write([]byte(`{"mobileNetworkCode":"21401","networkName":"vodafone ES","country":"ES","lastSeen":"2024-03-01 10:15:00"}`))
+		case "/networks":
+			if got := r.URL.RawQuery; got != "isos=ES" {
+				t.Errorf("Expected query 'isos=ES', got '%s'", got)
+			}
+			w.Write([]byte(`{"countryNetworks":[{"name":"Spain","networks":[
+				{"name":"Movistar","brandName":"Movistar","mcc":"214","mnc":"07","speed":["3G","4G"]},
+				{"name":"Other","brandName":"Other","mcc":"214","mnc":"001","speed":["4G"]},
+				{"name":"Vodafone","brandName":"Vodafone ES","mcc":"214","mnc":" 01","speed":["2G","3G","4G","5G"]}
+			]}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+
+	location, err := client.ESIMs.GetLocation(context.Background(), "8944500102198304826")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if location.MCC() != "214" || location.MNC() != "01" {
+		t.Errorf("Expected MCC 214 and MNC 01, got %s and %s", location.MCC(), location.MNC())
+	}
+	if want := "2024-03-01T10:15:00Z"; location.LastSeen.Format(time.RFC3339) != want {
+		t.Errorf("Expected last seen %s, got %s", want, location.LastSeen.Format(time.RFC3339))
+	}
+
+	resolved, err := client.ESIMs.ResolveLocation(context.Background(), "8944500102198304826")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resolved.Network == nil {
+		t.Fatal("Expected the network to be resolved")
+	}
+	if resolved.Network.BrandName != "Vodafone ES" || len(resolved.Network.Speed) != 4 {
+		t.Errorf("Unexpected network %+v", resolved.Network)
+	}
+	if resolved.CountryName != "Spain" {
+		t.Errorf("Expected country name 'Spain', got '%s'", resolved.CountryName)
+	}
+}
+
+func TestESIMHistory(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/esims/8944500102198304826/history" {
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+		gotQuery = r.URL.RawQuery
+		w.Write([]byte(`{"iccid":"8944500102198304826","pageCount":1,"actions":[
+			{"name":"eSIM Installed","date":"2024-02-28T09:00:00Z"},
+			{"name":"Bundle applied","bundleName":"esim_1GB_7D_ES_V2","date":"2024-03-01T10:00:00Z"},
+			{"name":"eSIM Enabled","date":"2024-03-01 10:05:00"},
+			{"name":"eSIM Disabled","date":"2024-03-05T18:00:00Z"},
+			{"name":"Bundle Depleted","bundleName":"esim_1GB_7D_ES_V2","date":"2024-03-06T12:00:00Z"},
+			{"name":"Profile refreshed","date":"2024-03-07T12:00:00Z"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+
+	history, err := client.ESIMs.History(context.Background(), "8944500102198304826", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if gotQuery != "" {
+		t.Errorf("Expected no query, got '%s'", gotQuery)
+	}
+
+	want := []ESIMEventType{
+		ESIMEventInstalled,
+		ESIMEventBundleApplied,
+		ESIMEventEnabled,
+		ESIMEventDisabled,
+		ESIMEventBundleDepleted,
+		ESIMEventOther,
+	}
+	if len(history.Actions) != len(want) {
+		t.Fatalf("Expected %d events, got %d", len(want), len(history.Actions))
+	}
+	for i, eventType := range want {
+		if history.Actions[i].Type != eventType {
+			t.Errorf("Expected event %d (%s) to be '%s', got '%s'", i, history.Actions[i].Name, eventType, history.Actions[i].Type)
+		}
+	}
+	if got := history.Actions[2].Date.Time; !got.Equal(time.Date(2024, 3, 1, 10, 5, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected enabled date %v", got)
+	}
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 5, 23, 59, 59, 0, time.UTC)
+	events, err := client.ESIMs.HistoryAll(context.Background(), "8944500102198304826", &HistoryRequest{
+		PerPage: 50,
+		From:    from,
+		To:      to,
+	}).Collect()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := "from=2024-03-01T00%3A00%3A00Z&page=1&perPage=50&to=2024-03-05T23%3A59%3A59Z"; gotQuery != want {
+		t.Errorf("Expected query '%s', got '%s'", want, gotQuery)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events in range, got %d", len(events))
+	}
+	if events[0].Type != ESIMEventBundleApplied || events[2].Type != ESIMEventDisabled {
+		t.Errorf("Unexpected events in range %+v", events)
+	}
+}
+
+func TestESIMHistoryAllSkipsFilteredPages(t *testing.T) {
+	pages := map[string]string{
+		"1": `{"actions":[{"name":"eSIM Installed","date":"2024-02-28T09:00:00Z"}]}`,
+		"2": `{"actions":[{"name":"eSIM Enabled","date":"2024-03-02T09:00:00Z"}]}`,
+		"3": `{"actions":[]}`,
+	}
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(pages[r.URL.Query().Get("page")]))
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+	events, err := client.ESIMs.HistoryAll(context.Background(), "8944500102198304826", &HistoryRequest{
+		From: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}).Collect()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) != 1 || events[0].Type != ESIMEventEnabled {
+		t.Errorf("Expected the enabled event of page 2, got %+v", events)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+}
+
+func TestClassifyESIMEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		want ESIMEventType
+	}{
+		{"eSIM Installed", ESIMEventInstalled},
+		{"PROFILE_INSTALLED", ESIMEventInstalled},
+		{"eSIM Uninstalled", ESIMEventOther},
+		{"Install failed", ESIMEventOther},
+		{"Bundle not applied", ESIMEventOther},
+		{"Bundle assigned", ESIMEventBundleApplied},
+		{"Bundle Depleted", ESIMEventBundleDepleted},
+		{"eSIM Disabled", ESIMEventDisabled},
+		{"eSIM re-enabled", ESIMEventEnabled},
+	}
+
+	for _, tt := range tests {
+		if got := classifyESIMEvent(tt.name); got != tt.want {
+			t.Errorf("classifyESIMEvent(%q): expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestESIMUpdate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/esims" {
+			t.Errorf("Expected PUT /esims, got %s %s", r.Method, r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch body["iccid"] {
+		case "8944500102198304826":
+			if body["customerRef"] != "customer-42" {
+				t.Errorf("Unexpected body %v", body)
+			}
+			w.Write([]byte(`{"iccid":"8944500102198304826","customerRef":"customer-42","profileStatus":"Installed"}`))
+		case "8944500102198304834":
+			if _, ok := body["customerRef"]; !ok {
+				t.Errorf("Expected an explicit empty customerRef, got %v", body)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"eSIM not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	ref := "customer-42"
+	esim, err := client.ESIMs.Update(ctx, "8944500102198304826", ESIMUpdate{CustomerRef: &ref})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if esim.CustomerRef != "customer-42" || esim.ProfileStatus != ProfileStatusInstalled {
+		t.Errorf("Unexpected eSIM %+v", esim)
+	}
+
+	empty := ""
+	result := client.ESIMs.UpdateMany(ctx, []ESIMUpdateItem{
+		{ICCID: "8944500102198304826", Update: ESIMUpdate{CustomerRef: &ref}},
+		{ICCID: "8944500102198304834", Update: ESIMUpdate{CustomerRef: &empty}},
+		{ICCID: "8944500102198304842", Update: ESIMUpdate{CustomerRef: &ref}},
+	})
+	if len(result.Results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(result.Results))
+	}
+	if got := result.Succeeded(); len(got) != 2 || got[1] != "8944500102198304834" {
+		t.Errorf("Unexpected succeeded ICCIDs %v", got)
+	}
+	if result.Results[1].ESIM.ICCID != "8944500102198304834" {
+		t.Errorf("Expected the ICCID to be set without a response body, got %+v", result.Results[1].ESIM)
+	}
+	failed := result.Failed()
+	if len(failed) != 1 || failed[0].ICCID != "8944500102198304842" || !errors.Is(failed[0].Err, ErrNotFound) {
+		t.Errorf("Unexpected failures %+v", failed)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	result = client.ESIMs.UpdateMany(cancelled, []ESIMUpdateItem{{ICCID: "8944500102198304826"}})
+	if !errors.Is(result.Results[0].Err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", result.Results[0].Err)
+	}
+}
@@ -32,8 +32,8 @@ func main() {
 			if i >= 3 {
 				break
 			}
-			fmt.Printf("  - %s: %dMB por %d días (€%.2f)\n",
-				bundle.Name, bundle.DataAmount, bundle.Duration, float64(bundle.Price)/100)
+			fmt.Printf("  - %s: %dMB por %d días (%s)\n",
+				bundle.Name, bundle.DataAmount, bundle.Duration, bundle.Price.Format("es-ES"))
 		}
 	}
 
@@ -53,7 +53,7 @@ func main() {
 			log.Printf("Error validando orden: %v", err)
 		} else {
 			fmt.Printf("✅ Orden válida: %t\n", validation.Valid)
-			fmt.Printf("💵 Total: %.2f %s\n", validation.Total, validation.Currency)
+			fmt.Printf("💵 Total: %s\n", validation.Total.Format("es-ES"))
 		}
 	}
 
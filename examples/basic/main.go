@@ -29,7 +29,7 @@ func main() {
 
 	for _, org := range org.Organizations {
 		fmt.Printf("✅ Organización: %s\n", org.Name)
-		fmt.Printf("💰 Balance: %d %s\n", org.Balance, org.Currency)
+		fmt.Printf("💰 Balance: %s\n", org.Balance)
 	}
 
 	// Listar algunos bundles del catálogo
@@ -45,7 +45,7 @@ func main() {
 			if i >= 3 { // Mostrar solo los primeros 3
 				break
 			}
-			fmt.Printf("  - %s: %s (Precio: %.2f)\n", bundle.Name, bundle.Description, bundle.Price)
+			fmt.Printf("  - %s: %s (Precio: %s)\n", bundle.Name, bundle.Description, bundle.Price)
 		}
 	}
 }
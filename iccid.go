@@ -0,0 +1,112 @@
+package esimgo
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidICCID reports that a string is not a valid ICCID
+var ErrInvalidICCID = errors.New("esimgo: invalid ICCID")
+
+const (
+	minICCIDLength = 18
+	maxICCIDLength = 22
+	// issuerPrefixLength is the length of the issuer identification number:
+	// the "89" telecom prefix, the country code and the issuer code (ITU-T
+	// E.118)
+	issuerPrefixLength = 7
+)
+
+// ICCID is the integrated circuit card identifier of an eSIM. Values decoded
+// from JSON are normalised but not validated; use ParseICCID or Validate to
+// check them.
+type ICCID string
+
+// ParseICCID normalises an ICCID, removing spaces, and checks that it has
+// 18 to 22 digits and a valid Luhn check digit
+func ParseICCID(value string) (ICCID, error) {
+	iccid := normaliseICCID(value)
+	if err := iccid.Validate(); err != nil {
+		return "", err
+	}
+	return iccid, nil
+}
+
+// normaliseICCID removes the whitespace found in printed ICCIDs
+func normaliseICCID(value string) ICCID {
+	return ICCID(strings.Join(strings.Fields(value), ""))
+}
+
+// Validate checks the length, digits and Luhn check digit of the ICCID.
+// Errors match ErrInvalidICCID.
+func (i ICCID) Validate() error {
+	if len(i) < minICCIDLength || len(i) > maxICCIDLength {
+		return fmt.Errorf("%w: %q must have %d to %d digits", ErrInvalidICCID, string(i), minICCIDLength, maxICCIDLength)
+	}
+	if strings.Trim(string(i), "0123456789") != "" {
+		return fmt.Errorf("%w: %q must only contain digits", ErrInvalidICCID, string(i))
+	}
+	if !luhnValid(string(i)) {
+		return fmt.Errorf("%w: %q has an invalid check digit", ErrInvalidICCID, string(i))
+	}
+	return nil
+}
+
+// String implements fmt.Stringer
+func (i ICCID) String() string {
+	return string(i)
+}
+
+// IssuerPrefix returns the issuer identification number of the ICCID: the
+// "89" telecom prefix followed by the country and issuer codes
+func (i ICCID) IssuerPrefix() string {
+	if len(i) < issuerPrefixLength {
+		return ""
+	}
+	return string(i[:issuerPrefixLength])
+}
+
+// CheckDigit returns the trailing Luhn check digit of the ICCID
+func (i ICCID) CheckDigit() byte {
+	if len(i) == 0 {
+		return 0
+	}
+	return i[len(i)-1]
+}
+
+// PathSegment returns the ICCID escaped for use in a URL path
+func (i ICCID) PathSegment() string {
+	return url.PathEscape(string(i))
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (i ICCID) MarshalText() ([]byte, error) {
+	return []byte(i), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, normalising the ICCID
+func (i *ICCID) UnmarshalText(text []byte) error {
+	*i = normaliseICCID(string(text))
+	return nil
+}
+
+// luhnValid reports whether a string of digits ends with a valid Luhn check
+// digit
+func luhnValid(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
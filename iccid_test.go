@@ -0,0 +1,84 @@
+package esimgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseICCID(t *testing.T) {
+	tests := []struct {
+		value string
+		want  ICCID
+		valid bool
+	}{
+		{"8944500102198304826", "8944500102198304826", true},
+		{"8944 5001 0219 8304 826", "8944500102198304826", true},
+		{" 898520002634012345670\n", "898520002634012345670", true},
+		{"8944500102198304827", "", false},
+		{"89445001021983048", "", false},
+		{"89445001021983048260000", "", false},
+		{"8944500102198304-826", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseICCID(tt.value)
+		if tt.valid && (err != nil || got != tt.want) {
+			t.Errorf("ParseICCID(%q): expected %s, got %s (%v)", tt.value, tt.want, got, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidICCID) {
+			t.Errorf("ParseICCID(%q): expected ErrInvalidICCID, got %v", tt.value, err)
+		}
+	}
+}
+
+func TestICCIDParts(t *testing.T) {
+	iccid := ICCID("8944500102198304826")
+	if got := iccid.IssuerPrefix(); got != "8944500" {
+		t.Errorf("Expected issuer prefix 8944500, got %s", got)
+	}
+	if got := iccid.CheckDigit(); got != '6' {
+		t.Errorf("Expected check digit 6, got %c", got)
+	}
+	if got := ICCID("89/44").PathSegment(); got != "89%2F44" {
+		t.Errorf("Expected escaped path segment, got %s", got)
+	}
+}
+
+func TestICCIDJSON(t *testing.T) {
+	var esim ESIM
+	if err := json.Unmarshal([]byte(`{"iccid":"8944 5001 0219 8304 826"}`), &esim); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if esim.ICCID != "8944500102198304826" {
+		t.Errorf("Expected a normalised ICCID, got %q", esim.ICCID)
+	}
+
+	data, err := json.Marshal(OrderItem{Type: BundleTypeBundle, Item: "esim_1GB_7D_GB_V2", Quantity: 1, ICCIDs: []ICCID{esim.ICCID}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := `{"type":"bundle","quantity":1,"item":"esim_1GB_7D_GB_V2","iccids":["8944500102198304826"]}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}
+
+func TestESIMGetDetailsEscapesICCID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/esims/8944%2F..%2Forganisation" || r.URL.RawQuery != "" {
+			t.Errorf("Expected the ICCID to be escaped, got %s?%s", r.URL.EscapedPath(), r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"iccid":"8944500102198304826"}`))
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+	if _, err := client.ESIMs.GetDetails(context.Background(), "8944/../organisation", ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,467 @@
+package esimgo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// idempotencyHeader is the header carrying the idempotency key of a
+	// transaction order
+	idempotencyHeader = "Idempotency-Key"
+	// orderInFlightTimeout is how long a pending submission is assumed to
+	// still be in flight before it is reconciled
+	orderInFlightTimeout = 2 * time.Minute
+	// reconcileSkew widens the reconciliation window to absorb clock skew
+	// between the client and the API
+	reconcileSkew = time.Minute
+)
+
+var (
+	// ErrDuplicateOrder reports that an order with the same idempotency key
+	// has already been placed
+	ErrDuplicateOrder = errors.New("esimgo: order already placed")
+	// ErrOrderInFlight reports that an order with the same idempotency key
+	// is still being submitted
+	ErrOrderInFlight = errors.New("esimgo: order submission in flight")
+	// ErrIdempotencyKeyReused reports that an idempotency key was used for
+	// an order with different contents
+	ErrIdempotencyKeyReused = errors.New("esimgo: idempotency key reused for a different order")
+	// ErrOrderOutcomeUnknown reports that an earlier submission under the
+	// same idempotency key may or may not have placed the order
+	ErrOrderOutcomeUnknown = errors.New("esimgo: order outcome unknown")
+)
+
+// DuplicateOrderError is returned when an order was already placed under
+// the same idempotency key. It matches ErrDuplicateOrder.
+type DuplicateOrderError struct {
+	IdempotencyKey string
+	// OrderReference is the reference of the order already placed, if known
+	OrderReference string
+}
+
+func (e *DuplicateOrderError) Error() string {
+	if e.OrderReference == "" {
+		return fmt.Sprintf("%s: idempotency key %s", ErrDuplicateOrder, e.IdempotencyKey)
+	}
+	return fmt.Sprintf("%s: idempotency key %s, order %s", ErrDuplicateOrder, e.IdempotencyKey, e.OrderReference)
+}
+
+// Is reports whether target is ErrDuplicateOrder
+func (e *DuplicateOrderError) Is(target error) bool {
+	return target == ErrDuplicateOrder
+}
+
+// OrderOutcomeUnknownError is returned when an earlier submission under the
+// same idempotency key timed out and orders with the same items were placed
+// since. The API does not echo the idempotency key, so those orders cannot
+// be told apart from orders placed by someone else. The journal entry stays
+// pending until the caller resolves it by saving it as completed or failed.
+// It matches ErrOrderOutcomeUnknown.
+type OrderOutcomeUnknownError struct {
+	IdempotencyKey string
+	// Candidates are the references of the orders that may be the one
+	// placed by the earlier submission
+	Candidates []string
+}
+
+func (e *OrderOutcomeUnknownError) Error() string {
+	return fmt.Sprintf("%s: idempotency key %s, candidate orders %s",
+		ErrOrderOutcomeUnknown, e.IdempotencyKey, strings.Join(e.Candidates, ", "))
+}
+
+// Is reports whether target is ErrOrderOutcomeUnknown
+func (e *OrderOutcomeUnknownError) Is(target error) bool {
+	return target == ErrOrderOutcomeUnknown
+}
+
+// NewIdempotencyKey returns a random key suitable for CreateOrderRequest
+func NewIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate idempotency key: %w", err)
+	}
+	// Format as a version 4 UUID
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+type idempotencyKeyKey struct{}
+
+// withIdempotencyKey returns a context whose requests carry the key
+func withIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+// idempotencyKey returns the key attached to ctx, if any
+func idempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyKey{}).(string)
+	return key
+}
+
+// JournalState represents the state of a journaled order submission
+type JournalState string
+
+// Journal states
+const (
+	// JournalStatePending means the outcome of the submission is unknown
+	JournalStatePending JournalState = "pending"
+	// JournalStateCompleted means the order was placed
+	JournalStateCompleted JournalState = "completed"
+	// JournalStateFailed means the API rejected the order, so it can be
+	// submitted again
+	JournalStateFailed JournalState = "failed"
+)
+
+// JournalEntry records a transaction order submitted under an idempotency key
+type JournalEntry struct {
+	Key   string       `json:"key"`
+	State JournalState `json:"state"`
+	// Fingerprint identifies the contents of the order
+	Fingerprint    string    `json:"fingerprint"`
+	OrderReference string    `json:"orderReference,omitempty"`
+	Attempts       int       `json:"attempts"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// OrderJournal stores the transaction orders submitted by a client, so that
+// duplicate submissions can be detected across retries and restarts
+type OrderJournal interface {
+	// Begin records entry unless its key is already known, in which case it
+	// returns the recorded entry and false
+	Begin(entry JournalEntry) (JournalEntry, bool, error)
+	// Save replaces the entry recorded under entry.Key
+	Save(entry JournalEntry) error
+}
+
+// MemoryOrderJournal is an OrderJournal kept in memory. It is safe for
+// concurrent use but does not survive restarts.
+type MemoryOrderJournal struct {
+	mu      sync.Mutex
+	entries map[string]JournalEntry
+}
+
+// NewMemoryOrderJournal creates an empty in-memory journal
+func NewMemoryOrderJournal() *MemoryOrderJournal {
+	return &MemoryOrderJournal{entries: make(map[string]JournalEntry)}
+}
+
+// Begin implements OrderJournal
+func (j *MemoryOrderJournal) Begin(entry JournalEntry) (JournalEntry, bool, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if existing, ok := j.entries[entry.Key]; ok {
+		return existing, false, nil
+	}
+	j.entries[entry.Key] = entry
+	return entry, true, nil
+}
+
+// Save implements OrderJournal
+func (j *MemoryOrderJournal) Save(entry JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries[entry.Key] = entry
+	return nil
+}
+
+// DefaultJournalRetention is how long a FileOrderJournal keeps completed
+// and failed entries by default
+const DefaultJournalRetention = 30 * 24 * time.Hour
+
+// compactMinRecords is the number of records below which a FileOrderJournal
+// is never compacted
+const compactMinRecords = 64
+
+// FileOrderJournal is an OrderJournal persisted as a file of JSON records,
+// one per line. Changes are appended, and the file is compacted when it is
+// opened and whenever it has doubled in size since the last compaction,
+// dropping the entries older than Retention.
+// It is safe for concurrent use within a process; the file must not be
+// shared between processes.
+type FileOrderJournal struct {
+	// Retention is how long completed and failed entries are kept after
+	// their last update. Pending entries are kept until they are resolved.
+	// Zero keeps every entry.
+	Retention time.Duration
+
+	mu      sync.Mutex
+	path    string
+	entries map[string]JournalEntry
+	// records counts the lines of the file, and compacted the lines left by
+	// the last compaction
+	records, compacted int
+}
+
+// NewFileOrderJournal creates a journal stored at path, keeping entries for
+// DefaultJournalRetention. The file is created on the first write.
+func NewFileOrderJournal(path string) *FileOrderJournal {
+	return &FileOrderJournal{path: path, Retention: DefaultJournalRetention}
+}
+
+// Begin implements OrderJournal
+func (j *FileOrderJournal) Begin(entry JournalEntry) (JournalEntry, bool, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.open(); err != nil {
+		return JournalEntry{}, false, err
+	}
+	if existing, ok := j.entries[entry.Key]; ok {
+		return existing, false, nil
+	}
+	if err := j.append(entry); err != nil {
+		return JournalEntry{}, false, err
+	}
+	return entry, true, nil
+}
+
+// Save implements OrderJournal
+func (j *FileOrderJournal) Save(entry JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.open(); err != nil {
+		return err
+	}
+	return j.append(entry)
+}
+
+// open loads the journal file the first time it is needed
+func (j *FileOrderJournal) open() error {
+	if j.entries != nil {
+		return nil
+	}
+
+	entries := make(map[string]JournalEntry)
+	records := 0
+	f, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		j.entries = entries
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read order journal: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("failed to decode order journal: %w", err)
+		}
+		entries[entry.Key] = entry
+		records++
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read order journal: %w", err)
+	}
+
+	j.entries, j.records, j.compacted = entries, records, records
+	if j.records > 0 {
+		return j.compact()
+	}
+	return nil
+}
+
+// append records an entry at the end of the journal file, compacting the
+// file when it has doubled since the last compaction
+func (j *FileOrderJournal) append(entry JournalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode order journal: %w", err)
+	}
+	f, err := os.OpenFile(j.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write order journal: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write order journal: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write order journal: %w", err)
+	}
+
+	j.entries[entry.Key] = entry
+	j.records++
+	if j.records >= compactMinRecords && j.records >= 2*j.compacted {
+		return j.compact()
+	}
+	return nil
+}
+
+// compact drops the expired entries and atomically rewrites the journal
+// file with one record per remaining entry
+func (j *FileOrderJournal) compact() error {
+	now := time.Now()
+	var buf bytes.Buffer
+	for key, entry := range j.entries {
+		if j.Retention > 0 && entry.State != JournalStatePending && now.Sub(entry.UpdatedAt) > j.Retention {
+			delete(j.entries, key)
+			continue
+		}
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to encode order journal: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(j.path), filepath.Base(j.path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write order journal: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write order journal: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write order journal: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), j.path); err != nil {
+		return fmt.Errorf("failed to write order journal: %w", err)
+	}
+	j.records, j.compacted = len(j.entries), len(j.entries)
+	return nil
+}
+
+// orderFingerprint identifies the contents of an order request
+func orderFingerprint(req *CreateOrderRequest) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// begin journals a transaction before it is submitted. Keys already known
+// are resolved: placed orders are reported as duplicates, recent pending
+// submissions as in flight, and stale pending submissions are reconciled
+// against the listed orders. A stale submission is retried only when no
+// order that could be its own was placed; otherwise its outcome is reported
+// as unknown.
+func (s *OrdersService) begin(ctx context.Context, journal OrderJournal, req *CreateOrderRequest) (JournalEntry, error) {
+	fingerprint, err := orderFingerprint(req)
+	if err != nil {
+		return JournalEntry{}, err
+	}
+
+	now := time.Now()
+	entry, created, err := journal.Begin(JournalEntry{
+		Key:         req.IdempotencyKey,
+		State:       JournalStatePending,
+		Fingerprint: fingerprint,
+		Attempts:    1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil || created {
+		return entry, err
+	}
+	if entry.Fingerprint != fingerprint {
+		return entry, ErrIdempotencyKeyReused
+	}
+
+	switch entry.State {
+	case JournalStateCompleted:
+		return entry, &DuplicateOrderError{IdempotencyKey: entry.Key, OrderReference: entry.OrderReference}
+	case JournalStatePending:
+		if now.Sub(entry.UpdatedAt) < orderInFlightTimeout {
+			return entry, ErrOrderInFlight
+		}
+		candidates, err := s.reconcile(ctx, entry, req)
+		if err != nil {
+			return entry, fmt.Errorf("failed to reconcile order: %w", err)
+		}
+		if len(candidates) > 0 {
+			return entry, &OrderOutcomeUnknownError{IdempotencyKey: entry.Key, Candidates: candidates}
+		}
+	}
+
+	entry.State = JournalStatePending
+	entry.Attempts++
+	entry.UpdatedAt = now
+	return entry, journal.Save(entry)
+}
+
+// finish records the outcome of a journaled transaction. Failures whose
+// outcome is unknown, such as timeouts, leave the entry pending so the next
+// submission is reconciled first.
+func (s *OrdersService) finish(ctx context.Context, journal OrderJournal, entry JournalEntry, resp *CreateOrderResponse, err error) {
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		entry.State = JournalStateCompleted
+		entry.OrderReference = resp.OrderReference
+	case errors.As(err, &apiErr) && apiErr.StatusCode < 500:
+		entry.State = JournalStateFailed
+	default:
+		return
+	}
+	entry.UpdatedAt = time.Now()
+
+	if err := journal.Save(entry); err != nil && s.client.logger != nil {
+		s.client.logger.WarnContext(ctx, "failed to journal eSIM Go order",
+			"idempotencyKey", entry.Key, "state", entry.State, "error", err)
+	}
+}
+
+// reconcile returns the references of the orders that may have been placed
+// by an earlier submission of req: those with the same items placed since
+// it was first submitted
+func (s *OrdersService) reconcile(ctx context.Context, entry JournalEntry, req *CreateOrderRequest) ([]string, error) {
+	since := entry.CreatedAt.Add(-reconcileSkew)
+	var candidates []string
+	it := s.ListAll(ctx, &ListOrdersRequest{From: since})
+	for it.Next() {
+		order := it.Value()
+		if !order.CreatedDate.Before(since) && orderMatches(&order, req) {
+			candidates = append(candidates, order.OrderReference)
+		}
+	}
+	return candidates, it.Err()
+}
+
+// orderMatches reports whether an order has the same line items as req
+func orderMatches(order *Order, req *CreateOrderRequest) bool {
+	if len(order.Items) != len(req.Order) {
+		return false
+	}
+	type line struct {
+		item     string
+		quantity int
+	}
+	remaining := make(map[line]int)
+	for _, item := range req.Order {
+		remaining[line{item.Item, item.Quantity}]++
+	}
+	for _, item := range order.Items {
+		key := line{item.Item, item.Quantity}
+		if remaining[key] == 0 {
+			return false
+		}
+		remaining[key]--
+	}
+	return true
+}
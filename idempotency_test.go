@@ -0,0 +1,295 @@
+package esimgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTransaction() *CreateOrderRequest {
+	return &CreateOrderRequest{
+		Type:   OrderTypeTransaction,
+		Assign: true,
+		Order:  []OrderItem{{Type: BundleTypeBundle, Quantity: 2, Item: "esim_1GB_7D_GB_V2"}},
+	}
+}
+
+func TestNewIdempotencyKey(t *testing.T) {
+	key, err := NewIdempotencyKey()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if !uuid.MatchString(key) {
+		t.Errorf("Expected a version 4 UUID, got %s", key)
+	}
+	other, _ := NewIdempotencyKey()
+	if other == key {
+		t.Error("Expected different keys")
+	}
+}
+
+func TestOrderCreateIdempotencyKey(t *testing.T) {
+	var keys []string
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"orderReference":"ord-1","status":"completed"}`))
+	}))
+	defer server.Close()
+
+	policy := &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL), WithRetryPolicy(policy))
+
+	// A failed transaction may have been placed, so it is never re-sent
+	req := newTransaction()
+	if _, err := client.Orders.Create(context.Background(), req); err == nil {
+		t.Fatal("Expected the 503 to be returned, got nil")
+	}
+	if len(keys) != 1 {
+		t.Errorf("Expected 1 attempt, got %d", len(keys))
+	}
+	if req.IdempotencyKey == "" {
+		t.Fatal("Expected the generated key to be stored in the request")
+	}
+
+	resp, err := client.Orders.Create(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.OrderReference != "ord-1" {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+	if len(keys) != 2 || keys[0] != req.IdempotencyKey || keys[1] != req.IdempotencyKey {
+		t.Errorf("Expected both submissions to carry key %s, got %v", req.IdempotencyKey, keys)
+	}
+
+	keys = nil
+	if _, err := client.Orders.Validate(context.Background(), req.Order, true); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "" {
+		t.Errorf("Expected validations to be sent without a key, got %v", keys)
+	}
+}
+
+func TestOrderCreateJournal(t *testing.T) {
+	var posts atomic.Int32
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"message":"bundle not available"}`))
+			return
+		}
+		w.Write([]byte(`{"orderReference":"ord-1","status":"completed"}`))
+	}))
+	defer server.Close()
+
+	journals := map[string]func(t *testing.T) OrderJournal{
+		"memory": func(t *testing.T) OrderJournal { return NewMemoryOrderJournal() },
+		"file": func(t *testing.T) OrderJournal {
+			return NewFileOrderJournal(filepath.Join(t.TempDir(), "orders.json"))
+		},
+	}
+	for name, newJournal := range journals {
+		t.Run(name, func(t *testing.T) {
+			posts.Store(0)
+			status = http.StatusOK
+			client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL), WithOrderJournal(newJournal(t)))
+			ctx := context.Background()
+
+			req := newTransaction()
+			if _, err := client.Orders.Create(ctx, req); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			_, err := client.Orders.Create(ctx, req)
+			var dup *DuplicateOrderError
+			if !errors.As(err, &dup) || !errors.Is(err, ErrDuplicateOrder) || dup.OrderReference != "ord-1" {
+				t.Errorf("Expected a duplicate of ord-1, got %v", err)
+			}
+
+			changed := newTransaction()
+			changed.IdempotencyKey = req.IdempotencyKey
+			changed.Order[0].Quantity = 3
+			if _, err := client.Orders.Create(ctx, changed); !errors.Is(err, ErrIdempotencyKeyReused) {
+				t.Errorf("Expected ErrIdempotencyKeyReused, got %v", err)
+			}
+			if got := posts.Load(); got != 1 {
+				t.Errorf("Expected 1 order to be sent, got %d", got)
+			}
+
+			// Orders rejected by the API can be submitted again
+			status = http.StatusBadRequest
+			rejected := newTransaction()
+			if _, err := client.Orders.Create(ctx, rejected); !errors.Is(err, ErrValidation) {
+				t.Fatalf("Expected ErrValidation, got %v", err)
+			}
+			status = http.StatusOK
+			if _, err := client.Orders.Create(ctx, rejected); err != nil {
+				t.Errorf("Expected the rejected order to be resubmitted, got %v", err)
+			}
+			if got := posts.Load(); got != 3 {
+				t.Errorf("Expected 3 orders to be sent, got %d", got)
+			}
+		})
+	}
+}
+
+func TestOrderCreateJournalPersists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"orderReference":"ord-1","status":"completed"}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "orders.json")
+	req := newTransaction()
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL), WithOrderJournal(NewFileOrderJournal(path)))
+	if _, err := client.Orders.Create(context.Background(), req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	restarted := NewESIMGoClient("test-api-key", WithBaseURL(server.URL), WithOrderJournal(NewFileOrderJournal(path)))
+	if _, err := restarted.Orders.Create(context.Background(), req); !errors.Is(err, ErrDuplicateOrder) {
+		t.Errorf("Expected ErrDuplicateOrder after a restart, got %v", err)
+	}
+}
+
+func TestFileOrderJournalCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.json")
+	journal := NewFileOrderJournal(path)
+	journal.Retention = time.Hour
+
+	now := time.Now()
+	stale := JournalEntry{Key: "stale", State: JournalStateCompleted, CreatedAt: now.Add(-2 * time.Hour), UpdatedAt: now.Add(-2 * time.Hour)}
+	stuck := JournalEntry{Key: "stuck", State: JournalStatePending, CreatedAt: now.Add(-2 * time.Hour), UpdatedAt: now.Add(-2 * time.Hour)}
+	for _, entry := range []JournalEntry{stale, stuck} {
+		if _, _, err := journal.Begin(entry); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	for i := 0; i < compactMinRecords; i++ {
+		entry := JournalEntry{Key: fmt.Sprintf("key-%d", i%4), State: JournalStatePending, CreatedAt: now, UpdatedAt: now}
+		if err := journal.Save(entry); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines >= compactMinRecords {
+		t.Errorf("Expected the journal to be compacted, got %d records", lines)
+	}
+
+	reopened := NewFileOrderJournal(path)
+	reopened.Retention = time.Hour
+	if _, created, _ := reopened.Begin(JournalEntry{Key: "stale"}); !created {
+		t.Error("Expected the expired completed entry to be dropped")
+	}
+	if entry, created, _ := reopened.Begin(JournalEntry{Key: "stuck"}); created || entry.State != JournalStatePending {
+		t.Errorf("Expected the pending entry to be kept, got %+v", entry)
+	}
+	if entry, created, _ := reopened.Begin(JournalEntry{Key: "key-3"}); created || entry.Key != "key-3" {
+		t.Errorf("Expected the recent entry to be kept, got %+v", entry)
+	}
+}
+
+func TestOrderCreateReconcile(t *testing.T) {
+	started := time.Now().Add(-10 * time.Minute)
+	tests := []struct {
+		name      string
+		updated   time.Time
+		orders    string
+		wantErr   error
+		wantPosts int32
+	}{
+		{
+			name:    "in flight",
+			updated: time.Now(),
+			orders:  `{"orders":[]}`,
+			wantErr: ErrOrderInFlight,
+		},
+		{
+			name:    "possibly placed",
+			updated: started,
+			orders: `{"orders":[{"orderReference":"ord-old","createdDate":"` + started.Add(-time.Hour).UTC().Format(time.RFC3339) + `","order":[{"item":"esim_1GB_7D_GB_V2","quantity":2}]},` +
+				`{"orderReference":"ord-other","createdDate":"` + started.UTC().Format(time.RFC3339) + `","order":[{"item":"esim_1GB_7D_GB_V2","quantity":1}]},` +
+				`{"orderReference":"ord-1","createdDate":"` + started.UTC().Format(time.RFC3339) + `","order":[{"item":"esim_1GB_7D_GB_V2","quantity":2}]},` +
+				`{"orderReference":"ord-3","createdDate":"` + started.Add(time.Minute).UTC().Format(time.RFC3339) + `","order":[{"item":"esim_1GB_7D_GB_V2","quantity":2}]}],"pageCount":1}`,
+			wantErr: ErrOrderOutcomeUnknown,
+		},
+		{
+			name:      "never placed",
+			updated:   started,
+			orders:    `{"orders":[{"orderReference":"ord-other","createdDate":"` + started.UTC().Format(time.RFC3339) + `","order":[{"item":"esim_1GB_7D_GB_V2","quantity":1}]}],"pageCount":1}`,
+			wantPosts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var posts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					w.Write([]byte(tt.orders))
+					return
+				}
+				posts.Add(1)
+				w.Write([]byte(`{"orderReference":"ord-2","status":"completed"}`))
+			}))
+			defer server.Close()
+
+			req := newTransaction()
+			req.IdempotencyKey = "key-1"
+			fingerprint, _ := orderFingerprint(req)
+			journal := NewMemoryOrderJournal()
+			journal.Save(JournalEntry{
+				Key:         "key-1",
+				State:       JournalStatePending,
+				Fingerprint: fingerprint,
+				Attempts:    1,
+				CreatedAt:   started,
+				UpdatedAt:   tt.updated,
+			})
+
+			client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL), WithOrderJournal(journal))
+			_, err := client.Orders.Create(context.Background(), req)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if got := posts.Load(); got != tt.wantPosts {
+				t.Errorf("Expected %d orders to be sent, got %d", tt.wantPosts, got)
+			}
+
+			var unknown *OrderOutcomeUnknownError
+			if errors.As(err, &unknown) && strings.Join(unknown.Candidates, ",") != "ord-1,ord-3" {
+				t.Errorf("Expected candidates ord-1 and ord-3, got %v", unknown.Candidates)
+			}
+			entry, _, _ := journal.Begin(JournalEntry{Key: "key-1"})
+			if tt.wantErr != nil && entry.State != JournalStatePending {
+				t.Errorf("Expected the journal entry to stay pending, got %+v", entry)
+			}
+		})
+	}
+}
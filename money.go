@@ -0,0 +1,470 @@
+package esimgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrCurrencyMismatch reports an operation between amounts in different
+// currencies
+var ErrCurrencyMismatch = errors.New("esimgo: currency mismatch")
+
+// ErrAmountOverflow reports an amount whose minor units do not fit in an
+// int64, about ±92 quadrillion major units of a two-decimal currency
+var ErrAmountOverflow = errors.New("esimgo: amount out of range")
+
+// currencyExponents lists the ISO 4217 currencies whose minor unit is not a
+// hundredth of the major unit
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0,
+	"XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// currencyExponent returns the number of decimals of a currency
+func currencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[currency]; ok {
+		return exponent
+	}
+	return 2
+}
+
+// Money is an exact amount of money in the minor unit of an ISO 4217
+// currency. The zero value is zero in an unspecified currency.
+type Money struct {
+	// Amount is expressed in the minor unit of the currency, such as cents.
+	// Without a currency it is in hundredths, unless the amount was parsed
+	// with more decimals, which are kept until WithCurrency attaches one.
+	Amount int64
+	// Currency is the ISO 4217 code, empty when the API did not say
+	Currency string
+	// exponent is the number of decimals of an amount without a currency
+	// when it has more than two; zero otherwise
+	exponent int
+}
+
+// NewMoney creates an amount from minor units
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// ParseMoney parses a decimal amount such as "12.34" in the given currency.
+// The amount may also carry its own currency code, as in "12.34 USD".
+// Digits beyond the minor unit are rounded half away from zero; amounts
+// without a currency keep every decimal.
+func ParseMoney(value, currency string) (Money, error) {
+	value = strings.TrimSpace(value)
+	if fields := strings.Fields(value); len(fields) == 2 {
+		switch {
+		case isCurrencyCode(fields[1]):
+			value, currency = fields[0], fields[1]
+		case isCurrencyCode(fields[0]):
+			value, currency = fields[1], fields[0]
+		}
+	}
+	currency = strings.ToUpper(currency)
+
+	exponent := currencyExponent(currency)
+	if currency == "" {
+		// The scale of the minor unit is unknown, so keep every decimal
+		exponent = max(exponent, fractionDigits(value))
+	}
+	amount, err := parseMinorUnits(value, exponent)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: currency}.withExponent(exponent), nil
+}
+
+// fractionDigits returns the number of significant decimals of an amount
+func fractionDigits(value string) int {
+	if strings.ContainsAny(value, "eE") {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0
+		}
+		value = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	_, fraction, _ := strings.Cut(value, ".")
+	return len(strings.TrimRight(fraction, "0"))
+}
+
+// isCurrencyCode reports whether s looks like an ISO 4217 code
+func isCurrencyCode(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// parseMinorUnits converts a decimal string to minor units without going
+// through floating point, unless it uses exponent notation
+func parseMinorUnits(value string, exponent int) (int64, error) {
+	if strings.ContainsAny(value, "eE") {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid amount %q", value)
+		}
+		value = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	negative := strings.HasPrefix(value, "-")
+	digits := strings.TrimPrefix(strings.TrimPrefix(value, "-"), "+")
+	whole, fraction, _ := strings.Cut(digits, ".")
+	if whole == "" && fraction == "" || strings.Trim(whole+fraction, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+
+	roundUp := false
+	if len(fraction) > exponent {
+		roundUp = fraction[exponent] >= '5'
+		fraction = fraction[:exponent]
+	}
+	fraction += strings.Repeat("0", exponent-len(fraction))
+
+	amount, err := strconv.ParseInt("0"+whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", value, err)
+	}
+	if roundUp {
+		amount++
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// decimals returns the number of decimals of the minor unit of m
+func (m Money) decimals() int {
+	if m.Currency == "" && m.exponent > 0 {
+		return m.exponent
+	}
+	return currencyExponent(m.Currency)
+}
+
+// withExponent sets the number of decimals of an amount without a currency,
+// dropping trailing zeros beyond the default two so that equal amounts
+// compare equal. Amounts with a currency are returned unchanged.
+func (m Money) withExponent(exponent int) Money {
+	m.exponent = 0
+	if m.Currency != "" {
+		return m
+	}
+	for exponent > currencyExponent("") && m.Amount%10 == 0 {
+		m.Amount /= 10
+		exponent--
+	}
+	if exponent > currencyExponent("") {
+		m.exponent = exponent
+	}
+	return m
+}
+
+// Decimal returns the amount in major units, such as "12.34"
+func (m Money) Decimal() string {
+	exponent := m.decimals()
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := strconv.FormatInt(amount, 10)
+	if exponent == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	split := len(digits) - exponent
+	return sign + digits[:split] + "." + digits[split:]
+}
+
+// String returns the amount followed by its currency, such as "12.34 USD"
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + m.Currency
+}
+
+// Float64 returns the amount in major units. It is meant for display and
+// statistics; use Money itself for arithmetic.
+func (m Money) Float64() float64 {
+	return float64(m.Amount) / math.Pow10(m.decimals())
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative reports whether the amount is below zero
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// operands returns the amounts of m and other in a common minor unit and
+// its number of decimals. Amounts
+// without a currency are only compatible with each other, since the scale
+// of the minor unit depends on the currency; use WithCurrency to attach one
+// first.
+func (m Money) operands(other Money) (int64, int64, int, error) {
+	if m.Currency != other.Currency {
+		return 0, 0, 0, fmt.Errorf("%w: %q and %q", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	exponent := max(m.decimals(), other.decimals())
+	a, err := rescale(m.Amount, m.decimals(), exponent)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	b, err := rescale(other.Amount, other.decimals(), exponent)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return a, b, exponent, nil
+}
+
+// rescale converts minor units with from decimals to minor units with to
+// decimals, failing when digits would be lost or the result overflows
+func rescale(amount int64, from, to int) (int64, error) {
+	for ; from < to; from++ {
+		if amount > math.MaxInt64/10 || amount < math.MinInt64/10 {
+			return 0, ErrAmountOverflow
+		}
+		amount *= 10
+	}
+	for ; from > to; from-- {
+		if amount%10 != 0 {
+			return 0, errors.New("amount has more decimals than its currency allows")
+		}
+		amount /= 10
+	}
+	return amount, nil
+}
+
+// WithCurrency returns an amount without a currency, such as a catalogue
+// price, in the given currency, rescaling its minor units. Amounts already
+// in another currency fail with ErrCurrencyMismatch, and amounts with more
+// decimals than the currency allows fail too.
+func (m Money) WithCurrency(currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	if m.Currency == currency {
+		return m, nil
+	}
+	if m.Currency != "" {
+		return Money{}, fmt.Errorf("%w: %q and %q", ErrCurrencyMismatch, m.Currency, currency)
+	}
+
+	amount, err := rescale(m.Amount, m.decimals(), currencyExponent(currency))
+	if err != nil {
+		return Money{}, fmt.Errorf("cannot express %s in %s: %w", m.Decimal(), currency, err)
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// Add returns m + other, failing with ErrAmountOverflow when the sum does
+// not fit in an int64
+func (m Money) Add(other Money) (Money, error) {
+	a, b, exponent, err := m.operands(other)
+	if err != nil {
+		return Money{}, err
+	}
+	if b > 0 && a > math.MaxInt64-b || b < 0 && a < math.MinInt64-b {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrAmountOverflow, m, other)
+	}
+	return Money{Amount: a + b, Currency: m.Currency}.withExponent(exponent), nil
+}
+
+// Sub returns m - other, failing with ErrAmountOverflow when the difference
+// does not fit in an int64
+func (m Money) Sub(other Money) (Money, error) {
+	a, b, exponent, err := m.operands(other)
+	if err != nil {
+		return Money{}, err
+	}
+	if b < 0 && a > math.MaxInt64+b || b > 0 && a < math.MinInt64+b {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrAmountOverflow, m, other)
+	}
+	return Money{Amount: a - b, Currency: m.Currency}.withExponent(exponent), nil
+}
+
+// Mul returns m multiplied by a quantity, failing with ErrAmountOverflow
+// when the product does not fit in an int64
+func (m Money) Mul(quantity int64) (Money, error) {
+	product := m.Amount * quantity
+	if m.Amount != 0 && (product/m.Amount != quantity || m.Amount == -1 && quantity == math.MinInt64 ||
+		quantity == -1 && m.Amount == math.MinInt64) {
+		return Money{}, fmt.Errorf("%w: %s × %d", ErrAmountOverflow, m, quantity)
+	}
+	return Money{Amount: product, Currency: m.Currency}.withExponent(m.decimals()), nil
+}
+
+// Cmp compares m and other, returning -1, 0 or +1
+func (m Money) Cmp(other Money) (int, error) {
+	a, b, _, err := m.operands(other)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case a < b:
+		return -1, nil
+	case a > b:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// moneyLocale describes how a language writes amounts of money
+type moneyLocale struct {
+	group, decimal string
+	// suffix places the symbol after the amount
+	suffix bool
+	// space separates the symbol from the amount
+	space bool
+}
+
+// moneyLocales is keyed by language; other languages are written like "en".
+// Spaces are non-breaking so that amounts are not split across lines.
+var moneyLocales = map[string]moneyLocale{
+	"en": {group: ",", decimal: "."},
+	"ja": {group: ",", decimal: "."},
+	"zh": {group: ",", decimal: "."},
+	"es": {group: ".", decimal: ",", suffix: true, space: true},
+	"de": {group: ".", decimal: ",", suffix: true, space: true},
+	"it": {group: ".", decimal: ",", suffix: true, space: true},
+	"nl": {group: ".", decimal: ",", space: true},
+	"pt": {group: ".", decimal: ",", space: true},
+	"fr": {group: "\u202f", decimal: ",", suffix: true, space: true},
+}
+
+// currencySymbols lists the symbols used instead of ISO codes
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"BRL": "R$",
+	"INR": "₹",
+}
+
+// Format writes the amount the way the language of locale does, such as
+// "$1,234.50" for "en-US" or "1.234,50 €" for "es-ES"
+func (m Money) Format(locale string) string {
+	language := strings.ToLower(locale)
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	conventions, ok := moneyLocales[language]
+	if !ok {
+		conventions = moneyLocales["en"]
+	}
+
+	decimal := m.Decimal()
+	negative := strings.HasPrefix(decimal, "-")
+	whole, fraction, _ := strings.Cut(strings.TrimPrefix(decimal, "-"), ".")
+	for i := len(whole) - 3; i > 0; i -= 3 {
+		whole = whole[:i] + conventions.group + whole[i:]
+	}
+	number := whole
+	if fraction != "" {
+		number += conventions.decimal + fraction
+	}
+
+	symbol, ok := currencySymbols[m.Currency]
+	if !ok {
+		symbol = m.Currency
+	}
+	if symbol == "" {
+		return signed(negative, number)
+	}
+	separator := ""
+	if conventions.space || symbol == m.Currency {
+		separator = "\u00a0"
+	}
+	if conventions.suffix {
+		return signed(negative, number+separator+symbol)
+	}
+	return signed(negative, symbol+separator+number)
+}
+
+// signed prefixes s with a minus sign when negative is set
+func signed(negative bool, s string) string {
+	if negative {
+		return "-" + s
+	}
+	return s
+}
+
+// MarshalJSON implements json.Marshaler, encoding the amount as a decimal
+// number in major units
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal()), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts numbers, strings
+// such as "12.34" or "12.34 USD" and objects with an amount (or value) and
+// a currency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	money, err := decodeMoney(data, "")
+	if err != nil {
+		return err
+	}
+	*m = money
+	return nil
+}
+
+// decodeMoney decodes an amount in any of the representations used by the
+// API. currency is used when the value does not carry its own, typically
+// because it is given by a sibling field.
+func decodeMoney(data []byte, currency string) (Money, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return NewMoney(0, currency), nil
+	}
+
+	switch data[0] {
+	case '"':
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return Money{}, err
+		}
+		if strings.TrimSpace(value) == "" {
+			return NewMoney(0, currency), nil
+		}
+		return ParseMoney(value, currency)
+	case '{':
+		var object struct {
+			Amount   json.RawMessage `json:"amount"`
+			Value    json.RawMessage `json:"value"`
+			Currency string          `json:"currency"`
+		}
+		if err := json.Unmarshal(data, &object); err != nil {
+			return Money{}, err
+		}
+		if object.Currency != "" {
+			currency = object.Currency
+		}
+		amount := object.Amount
+		if amount == nil {
+			amount = object.Value
+		}
+		if bytes.HasPrefix(bytes.TrimSpace(amount), []byte("{")) {
+			return Money{}, fmt.Errorf("invalid amount %s", data)
+		}
+		return decodeMoney(amount, currency)
+	}
+	return ParseMoney(string(data), currency)
+}
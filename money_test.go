@@ -0,0 +1,281 @@
+package esimgo
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		want     Money
+		valid    bool
+	}{
+		{"12.34", "usd", NewMoney(1234, "USD"), true},
+		{"12.3", "USD", NewMoney(1230, "USD"), true},
+		{"0.1", "USD", NewMoney(10, "USD"), true},
+		{"-4.5", "EUR", NewMoney(-450, "EUR"), true},
+		{"2.345", "USD", NewMoney(235, "USD"), true},
+		{"2.344", "USD", NewMoney(234, "USD"), true},
+		{"-2.345", "USD", NewMoney(-235, "USD"), true},
+		{"1500", "JPY", NewMoney(1500, "JPY"), true},
+		{"1.5", "KWD", NewMoney(1500, "KWD"), true},
+		{"12.34 EUR", "USD", NewMoney(1234, "EUR"), true},
+		{"GBP 7", "", NewMoney(700, "GBP"), true},
+		{"1e2", "USD", NewMoney(10000, "USD"), true},
+		{"", "USD", Money{}, false},
+		{"12,34", "USD", Money{}, false},
+		{"1.2.3", "USD", Money{}, false},
+		{"ten", "USD", Money{}, false},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.value, tt.currency)
+		if tt.valid && (err != nil || got != tt.want) {
+			t.Errorf("ParseMoney(%q, %q): expected %s, got %s (%v)", tt.value, tt.currency, tt.want, got, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("ParseMoney(%q, %q): expected an error, got %s", tt.value, tt.currency, got)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{NewMoney(1234, "USD"), "12.34 USD"},
+		{NewMoney(5, "EUR"), "0.05 EUR"},
+		{NewMoney(-5, "EUR"), "-0.05 EUR"},
+		{NewMoney(1500, "JPY"), "1500 JPY"},
+		{NewMoney(1500, "KWD"), "1.500 KWD"},
+		{NewMoney(280, ""), "2.80"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("Expected %s, got %s", tt.want, got)
+		}
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	price := NewMoney(140, "USD")
+
+	double, err := price.Mul(2)
+	if err != nil || double != NewMoney(280, "USD") {
+		t.Errorf("Expected 2.80 USD, got %s (%v)", double, err)
+	}
+	total, err := double.Add(NewMoney(10, "USD"))
+	if err != nil || total != NewMoney(290, "USD") {
+		t.Errorf("Expected 2.90 USD, got %s (%v)", total, err)
+	}
+	change, err := NewMoney(500, "USD").Sub(total)
+	if err != nil || change != NewMoney(210, "USD") {
+		t.Errorf("Expected 2.10 USD, got %s (%v)", change, err)
+	}
+	if sum, err := NewMoney(100, "").Add(NewMoney(50, "")); err != nil || sum != NewMoney(150, "") {
+		t.Errorf("Expected 1.50, got %s (%v)", sum, err)
+	}
+	if cmp, err := price.Cmp(total); err != nil || cmp != -1 {
+		t.Errorf("Expected -1, got %d (%v)", cmp, err)
+	}
+
+	if _, err := price.Add(NewMoney(100, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := price.Cmp(NewMoney(100, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+}
+
+func TestMoneyOverflow(t *testing.T) {
+	largest := NewMoney(math.MaxInt64, "USD")
+	smallest := NewMoney(math.MinInt64, "USD")
+	one := NewMoney(1, "USD")
+
+	if _, err := largest.Add(one); !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("Expected ErrAmountOverflow adding, got %v", err)
+	}
+	if _, err := smallest.Sub(one); !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("Expected ErrAmountOverflow subtracting, got %v", err)
+	}
+	if _, err := largest.Mul(2); !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("Expected ErrAmountOverflow multiplying, got %v", err)
+	}
+	if _, err := NewMoney(-1, "USD").Mul(math.MinInt64); !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("Expected ErrAmountOverflow multiplying by the smallest quantity, got %v", err)
+	}
+	if sum, err := largest.Add(NewMoney(-1, "USD")); err != nil || sum != NewMoney(math.MaxInt64-1, "USD") {
+		t.Errorf("Expected the largest amount minus one, got %s (%v)", sum, err)
+	}
+}
+
+func TestMoneyWithoutCurrency(t *testing.T) {
+	// A catalogue price has no currency, so its minor unit is a hundredth
+	price, err := ParseMoney("500", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	balance := NewMoney(500, "JPY")
+
+	if _, err := price.Cmp(balance); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch comparing with JPY, got %v", err)
+	}
+	if _, err := balance.Add(price); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch adding to JPY, got %v", err)
+	}
+
+	tests := []struct {
+		money    Money
+		currency string
+		want     Money
+		valid    bool
+	}{
+		{price, "jpy", NewMoney(500, "JPY"), true},
+		{price, "KWD", NewMoney(500000, "KWD"), true},
+		{price, "USD", NewMoney(50000, "USD"), true},
+		{NewMoney(1250, ""), "JPY", Money{}, false},
+		{NewMoney(280, "USD"), "USD", NewMoney(280, "USD"), true},
+		{NewMoney(280, "USD"), "EUR", Money{}, false},
+	}
+	for _, tt := range tests {
+		got, err := tt.money.WithCurrency(tt.currency)
+		if tt.valid && (err != nil || got != tt.want) {
+			t.Errorf("WithCurrency(%s, %s): expected %s, got %s (%v)", tt.money, tt.currency, tt.want, got, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("WithCurrency(%s, %s): expected an error, got %s", tt.money, tt.currency, got)
+		}
+	}
+
+	converted, _ := price.WithCurrency("JPY")
+	if cmp, err := converted.Cmp(balance); err != nil || cmp != 0 {
+		t.Errorf("Expected 500 JPY to equal the balance, got %d (%v)", cmp, err)
+	}
+}
+
+func TestMoneyWithoutCurrencyKeepsDecimals(t *testing.T) {
+	var bundle CatalogueBundle
+	if err := json.Unmarshal([]byte(`{"name":"esim_1GB_7D_KW_V2","price":1.234}`), &bundle); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := bundle.Price.String(); got != "1.234" {
+		t.Errorf("Expected 1.234, got %s", got)
+	}
+	if price, err := bundle.Price.WithCurrency("KWD"); err != nil || price != NewMoney(1234, "KWD") {
+		t.Errorf("Expected 1.234 KWD, got %s (%v)", price, err)
+	}
+	if price, err := bundle.Price.WithCurrency("USD"); err == nil {
+		t.Errorf("Expected an error converting to USD, got %s", price)
+	}
+
+	if price, err := ParseMoney("1.230", ""); err != nil || price != NewMoney(123, "") {
+		t.Errorf("Expected 1.23, got %s (%v)", price, err)
+	}
+	sum, err := bundle.Price.Add(NewMoney(1, ""))
+	if err != nil || sum.String() != "1.244" {
+		t.Errorf("Expected 1.244, got %s (%v)", sum, err)
+	}
+	sum, err = sum.Add(mustParseMoney(t, "0.006"))
+	if err != nil || sum != NewMoney(125, "") {
+		t.Errorf("Expected 1.25, got %s (%v)", sum, err)
+	}
+	if cmp, err := bundle.Price.Cmp(NewMoney(123, "")); err != nil || cmp != 1 {
+		t.Errorf("Expected 1.234 to exceed 1.23, got %d (%v)", cmp, err)
+	}
+}
+
+func mustParseMoney(t *testing.T, value string) Money {
+	t.Helper()
+	money, err := ParseMoney(value, "")
+	if err != nil {
+		t.Fatalf("Expected no error parsing %q, got %v", value, err)
+	}
+	return money
+}
+
+func TestMoneyFormat(t *testing.T) {
+	tests := []struct {
+		money  Money
+		locale string
+		want   string
+	}{
+		{NewMoney(123450, "USD"), "en-US", "$1,234.50"},
+		{NewMoney(123450, "EUR"), "es-ES", "1.234,50\u00a0€"},
+		{NewMoney(123450, "EUR"), "fr_FR", "1\u202f234,50\u00a0€"},
+		{NewMoney(123450, "BRL"), "pt-BR", "R$\u00a01.234,50"},
+		{NewMoney(-1234567, "USD"), "en", "-$12,345.67"},
+		{NewMoney(1500, "JPY"), "ja-JP", "¥1,500"},
+		{NewMoney(1500, "CHF"), "en-US", "CHF\u00a015.00"},
+		{NewMoney(1500, ""), "xx", "15.00"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.Format(tt.locale); got != tt.want {
+			t.Errorf("Format(%q): expected %q, got %q", tt.locale, tt.want, got)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	tests := []struct {
+		data string
+		want Money
+	}{
+		{`12.34`, NewMoney(1234, "")},
+		{`"12.34"`, NewMoney(1234, "")},
+		{`"12.34 USD"`, NewMoney(1234, "USD")},
+		{`{"amount":12.34,"currency":"EUR"}`, NewMoney(1234, "EUR")},
+		{`{"value":"1500","currency":"JPY"}`, NewMoney(1500, "JPY")},
+		{`null`, Money{}},
+		{`""`, Money{}},
+	}
+
+	for _, tt := range tests {
+		var got Money
+		if err := json.Unmarshal([]byte(tt.data), &got); err != nil || got != tt.want {
+			t.Errorf("Unmarshal(%s): expected %s, got %s (%v)", tt.data, tt.want, got, err)
+		}
+	}
+
+	var invalid Money
+	if err := json.Unmarshal([]byte(`"free"`), &invalid); err == nil {
+		t.Error("Expected an error decoding an invalid amount")
+	}
+
+	data, err := json.Marshal(NewMoney(1234, "USD"))
+	if err != nil || string(data) != "12.34" {
+		t.Errorf("Expected 12.34, got %s (%v)", data, err)
+	}
+}
+
+func TestMoneySiblingCurrency(t *testing.T) {
+	var org Organization
+	if err := json.Unmarshal([]byte(`{"currency":"KWD","balance":12.5,"testCredit":"1.25"}`), &org); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if org.Balance != NewMoney(12500, "KWD") || org.TestCredit != NewMoney(1250, "KWD") {
+		t.Errorf("Expected balances in KWD, got %s and %s", org.Balance, org.TestCredit)
+	}
+
+	var order Order
+	if err := json.Unmarshal([]byte(`{"currency":"JPY","total":3000,"order":[{"quantity":2,"subTotal":3000,"pricePerUnit":1500}]}`), &order); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if order.Total != NewMoney(3000, "JPY") || order.Items[0].PricePerUnit != NewMoney(1500, "JPY") {
+		t.Errorf("Expected amounts in JPY, got %s and %s", order.Total, order.Items[0].PricePerUnit)
+	}
+
+	var resp CreateOrderResponse
+	if err := json.Unmarshal([]byte(`{"currency":"EUR","total":"2.80","valid":true}`), &resp); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Total != NewMoney(280, "EUR") || !resp.Valid || resp.Raw["total"] == nil {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+}
@@ -71,3 +71,25 @@ func (s *NetworksService) GetCountryNetworks(ctx context.Context, req *GetNetwor
 	}
 	return &resp, nil
 }
+
+// FindNetwork returns the network with the given MCC and MNC, along with
+// the country it belongs to
+func (r *NetworksResponse) FindNetwork(mcc, mnc string) (*Network, *CountryNetwork, bool) {
+	for i := range r.CountryNetworks {
+		country := &r.CountryNetworks[i]
+		for j := range country.Networks {
+			network := &country.Networks[j]
+			if sameNetworkCode(network.MCC, mcc) && sameNetworkCode(network.MNC, mnc) {
+				return network, country, true
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+// sameNetworkCode compares MCC or MNC codes, ignoring surrounding
+// whitespace. Leading zeros are significant: a 2-digit MNC such as "10" is a
+// different network from the 3-digit "010".
+func sameNetworkCode(a, b string) bool {
+	return strings.TrimSpace(a) == strings.TrimSpace(b)
+}
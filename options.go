@@ -0,0 +1,84 @@
+package esimgo
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures a Client at construction time
+type Option func(*Client)
+
+// RequestFunc sends an HTTP request and returns its response
+type RequestFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the function that sends every request, allowing callers
+// to add headers, tracing or metrics around each attempt
+type Middleware func(next RequestFunc) RequestFunc
+
+// WithBaseURL sets the API base URL (useful for testing)
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithTimeout sets the timeout of every HTTP request. The HTTP client is
+// copied, so a client passed to WithHTTPClient is never modified.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithRateLimiter sets the limiter shared by every service using the client
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// WithLogger sets the logger used to report requests and retries
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithMiddleware appends middleware around the sending of every request.
+// The first middleware given is the outermost one.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// WithOrderJournal sets the journal used to detect duplicate submissions of
+// transaction orders
+func WithOrderJournal(journal OrderJournal) Option {
+	return func(c *Client) {
+		c.orderJournal = journal
+	}
+}
@@ -0,0 +1,98 @@
+package esimgo
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClientOptions(t *testing.T) {
+	httpClient := &http.Client{Timeout: time.Minute}
+	policy := DefaultRetryPolicy()
+	limiter := NewRateLimiter(RateLimit{Rate: 5, Burst: 5})
+
+	client := NewClient("test-api-key",
+		WithBaseURL("https://sandbox.example.com/v2.4/"),
+		WithHTTPClient(httpClient),
+		WithTimeout(5*time.Second),
+		WithUserAgent("price-sync/1.0"),
+		WithRetryPolicy(policy),
+		WithRateLimiter(limiter),
+	)
+
+	if client.baseURL != "https://sandbox.example.com/v2.4" {
+		t.Errorf("Expected trimmed base URL, got '%s'", client.baseURL)
+	}
+	if client.httpClient.Timeout != 5*time.Second {
+		t.Errorf("Expected timeout 5s, got %v", client.httpClient.Timeout)
+	}
+	if httpClient.Timeout != time.Minute {
+		t.Errorf("Expected the caller's HTTP client to be left untouched, got timeout %v", httpClient.Timeout)
+	}
+	if client.userAgent != "price-sync/1.0" {
+		t.Errorf("Expected user agent 'price-sync/1.0', got '%s'", client.userAgent)
+	}
+	if client.retryPolicy != policy {
+		t.Error("Expected the retry policy to be set")
+	}
+	if client.rateLimiter != limiter {
+		t.Error("Expected the rate limiter to be set")
+	}
+}
+
+func TestNewESIMGoClientOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("User-Agent"); got != "price-sync/1.0" {
+			t.Errorf("Expected User-Agent 'price-sync/1.0', got '%s'", got)
+		}
+		if got := r.Header.Get("X-Trace-Id"); got != "trace-1" {
+			t.Errorf("Expected X-Trace-Id 'trace-1', got '%s'", got)
+		}
+		w.Write([]byte(`{"organisations":[{"name":"Test Organization"}]}`))
+	}))
+	defer server.Close()
+
+	var order []string
+	tracing := func(next RequestFunc) RequestFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			order = append(order, "tracing")
+			req.Header.Set("X-Trace-Id", "trace-1")
+			return next(req)
+		}
+	}
+	metrics := func(next RequestFunc) RequestFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			order = append(order, "metrics")
+			return next(req)
+		}
+	}
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client := NewESIMGoClient("test-api-key",
+		WithBaseURL(server.URL),
+		WithUserAgent("price-sync/1.0"),
+		WithLogger(logger),
+		WithMiddleware(tracing, metrics),
+	)
+
+	orgs, err := client.Organization.GetDetails(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(orgs.Organizations) != 1 || orgs.Organizations[0].Name != "Test Organization" {
+		t.Errorf("Unexpected organisations %+v", orgs.Organizations)
+	}
+	if strings.Join(order, ",") != "tracing,metrics" {
+		t.Errorf("Expected middleware order 'tracing,metrics', got '%s'", strings.Join(order, ","))
+	}
+	if !strings.Contains(logs.String(), "endpoint=/organisation") {
+		t.Errorf("Expected the request to be logged, got '%s'", logs.String())
+	}
+}
@@ -0,0 +1,180 @@
+package esimgo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxOrderQuantity is the largest quantity accepted for a single order item
+const MaxOrderQuantity = 1000
+
+// FieldError describes one invalid field of a request
+type FieldError struct {
+	// Field is the path of the field, such as "order[0].quantity"
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors aggregates the problems found by client-side validation.
+// It matches ErrValidation.
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrValidation, strings.Join(messages, "; "))
+}
+
+// Is reports whether target is ErrValidation
+func (e ValidationErrors) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// add records a problem with a field
+func (e *ValidationErrors) add(field, format string, args ...interface{}) {
+	*e = append(*e, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns the errors, or nil when there are none
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate checks the request without calling the API and returns the
+// problems found as ValidationErrors
+func (r *CreateOrderRequest) Validate() error {
+	var errs ValidationErrors
+	if r.Type != OrderTypeValidate && r.Type != OrderTypeTransaction {
+		errs.add("type", "must be %q or %q, got %q", OrderTypeValidate, OrderTypeTransaction, r.Type)
+	}
+	if len(r.Order) == 0 {
+		errs.add("order", "must contain at least one item")
+	}
+	for i, item := range r.Order {
+		item.validate(fmt.Sprintf("order[%d]", i), &errs)
+	}
+	return errs.err()
+}
+
+// validate records the problems of an order item
+func (i *OrderItem) validate(field string, errs *ValidationErrors) {
+	if i.Type != BundleTypeBundle {
+		errs.add(field+".type", "must be %q, got %q", BundleTypeBundle, i.Type)
+	}
+	if strings.TrimSpace(i.Item) == "" {
+		errs.add(field+".item", "is required")
+	}
+	if i.Quantity < 1 || i.Quantity > MaxOrderQuantity {
+		errs.add(field+".quantity", "must be between 1 and %d, got %d", MaxOrderQuantity, i.Quantity)
+	}
+
+	if len(i.ICCIDs) == 0 {
+		if i.AllowReassign {
+			errs.add(field+".allowReassign", "requires ICCIDs")
+		}
+		return
+	}
+	if len(i.ICCIDs) != i.Quantity {
+		errs.add(field+".iccids", "has %d ICCIDs for a quantity of %d", len(i.ICCIDs), i.Quantity)
+	}
+	seen := make(map[ICCID]bool, len(i.ICCIDs))
+	for j, iccid := range i.ICCIDs {
+		if err := iccid.Validate(); err != nil {
+			errs.add(fmt.Sprintf("%s.iccids[%d]", field, j), "%v", err)
+			continue
+		}
+		if seen[iccid] {
+			errs.add(fmt.Sprintf("%s.iccids[%d]", field, j), "duplicates ICCID %s", iccid)
+		}
+		seen[iccid] = true
+	}
+}
+
+// OrderBuilder builds a CreateOrderRequest, validating it before any call
+// to the API:
+//
+//	req, err := esimgo.NewOrderBuilder(esimgo.OrderTypeTransaction).
+//		Assign(true).
+//		Bundle("esim_1GB_7D_GB_V2", 5).
+//		BundleForICCIDs("esim_5GB_30D_US_V2", "8944500102198304826").
+//		AllowReassign().
+//		Build()
+type OrderBuilder struct {
+	req  CreateOrderRequest
+	errs ValidationErrors
+}
+
+// NewOrderBuilder starts an order of the given type, OrderTypeValidate or
+// OrderTypeTransaction
+func NewOrderBuilder(orderType string) *OrderBuilder {
+	return &OrderBuilder{req: CreateOrderRequest{Type: orderType}}
+}
+
+// Assign sets whether the bundles are assigned to eSIMs
+func (b *OrderBuilder) Assign(assign bool) *OrderBuilder {
+	b.req.Assign = assign
+	return b
+}
+
+// IdempotencyKey sets the idempotency key of a transaction
+func (b *OrderBuilder) IdempotencyKey(key string) *OrderBuilder {
+	b.req.IdempotencyKey = key
+	return b
+}
+
+// Item adds an order item as is
+func (b *OrderBuilder) Item(item OrderItem) *OrderBuilder {
+	b.req.Order = append(b.req.Order, item)
+	return b
+}
+
+// Bundle adds a quantity of a bundle
+func (b *OrderBuilder) Bundle(name string, quantity int) *OrderBuilder {
+	return b.Item(OrderItem{Type: BundleTypeBundle, Item: name, Quantity: quantity})
+}
+
+// BundleForICCIDs adds a bundle for each of the given eSIMs
+func (b *OrderBuilder) BundleForICCIDs(name string, iccids ...ICCID) *OrderBuilder {
+	return b.Item(OrderItem{Type: BundleTypeBundle, Item: name, Quantity: len(iccids), ICCIDs: iccids})
+}
+
+// AllowReassign allows the bundle of the last item to be reassigned to its
+// ICCIDs
+func (b *OrderBuilder) AllowReassign() *OrderBuilder {
+	if len(b.req.Order) == 0 {
+		b.errs.add("allowReassign", "must follow an item")
+		return b
+	}
+	b.req.Order[len(b.req.Order)-1].AllowReassign = true
+	return b
+}
+
+// Build validates the order and returns the request, or ValidationErrors
+// listing every problem found
+func (b *OrderBuilder) Build() (*CreateOrderRequest, error) {
+	errs := append(ValidationErrors(nil), b.errs...)
+	if err := b.req.Validate(); err != nil {
+		var validationErrs ValidationErrors
+		if !errors.As(err, &validationErrs) {
+			return nil, err
+		}
+		errs = append(errs, validationErrs...)
+	}
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	req := b.req
+	req.Order = append([]OrderItem(nil), b.req.Order...)
+	return &req, nil
+}
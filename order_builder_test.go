@@ -0,0 +1,111 @@
+package esimgo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestOrderBuilder(t *testing.T) {
+	req, err := NewOrderBuilder(OrderTypeTransaction).
+		Assign(true).
+		IdempotencyKey("key-1").
+		Bundle("esim_1GB_7D_GB_V2", 5).
+		BundleForICCIDs("esim_5GB_30D_US_V2", "8944500102198304826", "8944500102198304834").
+		AllowReassign().
+		Build()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if req.Type != OrderTypeTransaction || !req.Assign || req.IdempotencyKey != "key-1" || len(req.Order) != 2 {
+		t.Fatalf("Unexpected request %+v", req)
+	}
+	if item := req.Order[0]; item.Type != BundleTypeBundle || item.Quantity != 5 || item.AllowReassign {
+		t.Errorf("Unexpected first item %+v", item)
+	}
+	if item := req.Order[1]; item.Quantity != 2 || !item.AllowReassign || len(item.ICCIDs) != 2 {
+		t.Errorf("Unexpected second item %+v", item)
+	}
+}
+
+func TestOrderBuilderValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *OrderBuilder
+		fields  []string
+	}{
+		{
+			name:    "no items",
+			builder: NewOrderBuilder(OrderTypeValidate),
+			fields:  []string{"order"},
+		},
+		{
+			name:    "unknown order type",
+			builder: NewOrderBuilder("purchase").Bundle("esim_1GB_7D_GB_V2", 1),
+			fields:  []string{"type"},
+		},
+		{
+			name:    "empty item and quantity",
+			builder: NewOrderBuilder(OrderTypeTransaction).Bundle("", 0),
+			fields:  []string{"order[0].item", "order[0].quantity"},
+		},
+		{
+			name:    "quantity too large",
+			builder: NewOrderBuilder(OrderTypeTransaction).Bundle("esim_1GB_7D_GB_V2", MaxOrderQuantity+1),
+			fields:  []string{"order[0].quantity"},
+		},
+		{
+			name:    "unknown item type",
+			builder: NewOrderBuilder(OrderTypeTransaction).Item(OrderItem{Type: "topup", Item: "esim_1GB_7D_GB_V2", Quantity: 1}),
+			fields:  []string{"order[0].type"},
+		},
+		{
+			name: "ICCIDs mismatching quantity",
+			builder: NewOrderBuilder(OrderTypeTransaction).Item(OrderItem{
+				Type: BundleTypeBundle, Item: "esim_1GB_7D_GB_V2", Quantity: 3, ICCIDs: []ICCID{"8944500102198304826"},
+			}),
+			fields: []string{"order[0].iccids"},
+		},
+		{
+			name: "invalid ICCIDs",
+			builder: NewOrderBuilder(OrderTypeTransaction).
+				BundleForICCIDs("esim_1GB_7D_GB_V2", "8944500102198304827", "89445001", "89445001021983048AB", "8944500102198304826", "8944500102198304826"),
+			fields: []string{"order[0].iccids[0]", "order[0].iccids[1]", "order[0].iccids[2]", "order[0].iccids[4]"},
+		},
+		{
+			name:    "reassign without ICCIDs",
+			builder: NewOrderBuilder(OrderTypeTransaction).Bundle("esim_1GB_7D_GB_V2", 1).AllowReassign(),
+			fields:  []string{"order[0].allowReassign"},
+		},
+		{
+			name:    "reassign before any item",
+			builder: NewOrderBuilder(OrderTypeTransaction).AllowReassign().Bundle("esim_1GB_7D_GB_V2", 1),
+			fields:  []string{"allowReassign"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := tt.builder.Build()
+			if req != nil {
+				t.Errorf("Expected no request, got %+v", req)
+			}
+			if !errors.Is(err, ErrValidation) {
+				t.Fatalf("Expected ErrValidation, got %v", err)
+			}
+			var errs ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Expected ValidationErrors, got %T", err)
+			}
+
+			var fields []string
+			for _, fieldErr := range errs {
+				fields = append(fields, fieldErr.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("Expected errors on %v, got %v", tt.fields, errs)
+			}
+		})
+	}
+}
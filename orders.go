@@ -2,7 +2,10 @@ package esimgo
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -18,11 +21,11 @@ func NewOrdersService(client *Client) *OrdersService {
 
 // OrderItem represents an order item for creating orders
 type OrderItem struct {
-	Type          string   `json:"type"`
-	Quantity      int      `json:"quantity"`
-	Item          string   `json:"item"`
-	ICCIDs        []string `json:"iccids,omitempty"`
-	AllowReassign bool     `json:"allowReassign,omitempty"`
+	Type          string  `json:"type"`
+	Quantity      int     `json:"quantity"`
+	Item          string  `json:"item"`
+	ICCIDs        []ICCID `json:"iccids,omitempty"`
+	AllowReassign bool    `json:"allowReassign,omitempty"`
 }
 
 // CreateOrderRequest represents a request to create an order
@@ -30,19 +33,111 @@ type CreateOrderRequest struct {
 	Type   string      `json:"type"`   // "validate" or "transaction"
 	Assign bool        `json:"assign"` // auto-assign bundles to eSIMs
 	Order  []OrderItem `json:"order"`
+	// IdempotencyKey identifies a transaction across resubmissions. Create
+	// generates one when it is empty and stores it back in the request. It
+	// is sent in the Idempotency-Key header, which is only a hint: the API
+	// does not document honouring it. Resubmissions are only detected, and
+	// refused, when the client has an OrderJournal (see WithOrderJournal).
+	IdempotencyKey string `json:"-"`
 }
 
 // CreateOrderResponse represents the response from creating an order
 type CreateOrderResponse struct {
-	Total       float64   `json:"total"`
-	Valid       bool      `json:"valid"`
-	Currency    string    `json:"currency"`
-	CreatedDate time.Time `json:"createdDate"`
-	Assigned    bool      `json:"assigned"`
+	OrderReference string          `json:"orderReference,omitempty"`
+	Status         string          `json:"status,omitempty"`
+	StatusMessage  string          `json:"statusMessage,omitempty"`
+	Items          []OrderLineItem `json:"order,omitempty"`
+	Total          Money           `json:"total"`
+	Valid          bool            `json:"valid"`
+	Currency       string          `json:"currency"`
+	CreatedDate    Timestamp       `json:"createdDate"`
+	Assigned       bool            `json:"assigned"`
+	// ESIMs holds the install details of the eSIMs assigned by a transaction
+	ESIMs []InstallDetails `json:"esims,omitempty"`
+	// Raw holds every field of the response, including the ones not
+	// modelled above
+	Raw map[string]json.RawMessage `json:"-"`
 }
 
-// Create creates a new order
+// UnmarshalJSON implements json.Unmarshaler, keeping the raw fields and
+// decoding the amounts in the currency of the order
+func (r *CreateOrderResponse) UnmarshalJSON(data []byte) error {
+	type createOrderResponse CreateOrderResponse
+	var decoded struct {
+		createOrderResponse
+		Total json.RawMessage   `json:"total"`
+		Items []json.RawMessage `json:"order"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*r = CreateOrderResponse(decoded.createOrderResponse)
+	var err error
+	if r.Total, r.Items, err = decodeOrderAmounts(decoded.Total, decoded.Items, r.Currency); err != nil {
+		return err
+	}
+	r.Raw = raw
+	return nil
+}
+
+// ICCIDs returns the ICCIDs bought by the order, taken from the assigned
+// eSIMs or, when the API did not return them, from the line items
+func (r *CreateOrderResponse) ICCIDs() []ICCID {
+	var iccids []ICCID
+	for _, esim := range r.ESIMs {
+		if esim.ICCID != "" {
+			iccids = append(iccids, esim.ICCID)
+		}
+	}
+	if len(iccids) > 0 {
+		return iccids
+	}
+	order := Order{Items: r.Items}
+	return order.ICCIDs()
+}
+
+// Create creates a new order.
+//
+// Transactions are sent with an idempotency key. They are never retried
+// automatically, since a failed attempt may still have placed the order;
+// callers that retry should resubmit the same request. When the client has
+// an OrderJournal, submissions of a key that was already used fail with a
+// *DuplicateOrderError, ErrOrderInFlight, ErrIdempotencyKeyReused or an
+// *OrderOutcomeUnknownError instead of being sent again.
 func (s *OrdersService) Create(ctx context.Context, req *CreateOrderRequest) (*CreateOrderResponse, error) {
+	if req.Type != OrderTypeTransaction {
+		return s.create(ctx, req)
+	}
+
+	if req.IdempotencyKey == "" {
+		key, err := NewIdempotencyKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create order: %w", err)
+		}
+		req.IdempotencyKey = key
+	}
+	ctx = withIdempotencyKey(ctx, req.IdempotencyKey)
+
+	journal := s.client.orderJournal
+	if journal == nil {
+		return s.create(ctx, req)
+	}
+	entry, err := s.begin(ctx, journal, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+	resp, err := s.create(ctx, req)
+	s.finish(ctx, journal, entry, resp, err)
+	return resp, err
+}
+
+// create sends an order request
+func (s *OrdersService) create(ctx context.Context, req *CreateOrderRequest) (*CreateOrderResponse, error) {
 	var resp CreateOrderResponse
 	err := s.client.makeRequest(ctx, "POST", "/orders", req, &resp)
 	if err != nil {
@@ -66,3 +161,189 @@ func (s *OrdersService) Validate(ctx context.Context, orderItems []OrderItem, as
 	}
 	return &resp, nil
 }
+
+// OrderLineItem represents an item of a placed order
+type OrderLineItem struct {
+	Type          string  `json:"type"`
+	Item          string  `json:"item"`
+	Quantity      int     `json:"quantity"`
+	ICCIDs        []ICCID `json:"iccids,omitempty"`
+	SubTotal      Money   `json:"subTotal"`
+	PricePerUnit  Money   `json:"pricePerUnit"`
+	AllowReassign bool    `json:"allowReassign,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (i *OrderLineItem) UnmarshalJSON(data []byte) error {
+	return i.decode(data, "")
+}
+
+// decode decodes a line item whose amounts are in the given currency
+func (i *OrderLineItem) decode(data []byte, currency string) error {
+	type orderLineItem OrderLineItem
+	var decoded struct {
+		orderLineItem
+		SubTotal     json.RawMessage `json:"subTotal"`
+		PricePerUnit json.RawMessage `json:"pricePerUnit"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*i = OrderLineItem(decoded.orderLineItem)
+	var err error
+	if i.SubTotal, err = decodeMoney(decoded.SubTotal, currency); err != nil {
+		return fmt.Errorf("invalid subTotal: %w", err)
+	}
+	if i.PricePerUnit, err = decodeMoney(decoded.PricePerUnit, currency); err != nil {
+		return fmt.Errorf("invalid pricePerUnit: %w", err)
+	}
+	return nil
+}
+
+// decodeOrderAmounts decodes the total and line items of an order in the
+// currency of the order
+func decodeOrderAmounts(total json.RawMessage, items []json.RawMessage, currency string) (Money, []OrderLineItem, error) {
+	amount, err := decodeMoney(total, currency)
+	if err != nil {
+		return Money{}, nil, fmt.Errorf("invalid total: %w", err)
+	}
+	if items == nil {
+		return amount, nil, nil
+	}
+	lineItems := make([]OrderLineItem, len(items))
+	for i, item := range items {
+		if err := lineItems[i].decode(item, currency); err != nil {
+			return Money{}, nil, err
+		}
+	}
+	return amount, lineItems, nil
+}
+
+// Order represents a placed order
+type Order struct {
+	OrderReference string          `json:"orderReference"`
+	Status         string          `json:"status"`
+	StatusMessage  string          `json:"statusMessage,omitempty"`
+	Items          []OrderLineItem `json:"order"`
+	Total          Money           `json:"total"`
+	Currency       string          `json:"currency"`
+	CreatedDate    Timestamp       `json:"createdDate"`
+	Assigned       bool            `json:"assigned"`
+	SourceIP       string          `json:"sourceIP,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding the amounts in the
+// currency of the order
+func (o *Order) UnmarshalJSON(data []byte) error {
+	type order Order
+	var decoded struct {
+		order
+		Total json.RawMessage   `json:"total"`
+		Items []json.RawMessage `json:"order"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*o = Order(decoded.order)
+	var err error
+	o.Total, o.Items, err = decodeOrderAmounts(decoded.Total, decoded.Items, o.Currency)
+	return err
+}
+
+// ICCIDs returns the ICCIDs of every line item of the order
+func (o *Order) ICCIDs() []ICCID {
+	var iccids []ICCID
+	for _, item := range o.Items {
+		for _, iccid := range item.ICCIDs {
+			if iccid != "" {
+				iccids = append(iccids, iccid)
+			}
+		}
+	}
+	return iccids
+}
+
+// Orders represents a page of orders
+type Orders struct {
+	Orders []Order `json:"orders"`
+	PageInfo
+}
+
+// ListOrdersRequest represents query parameters for listing orders
+type ListOrdersRequest struct {
+	Page    int `json:"page,omitempty"`
+	PerPage int `json:"perPage,omitempty"`
+	// From and To restrict the orders to a creation time range; zero values
+	// leave the range open
+	From time.Time `json:"from,omitempty"`
+	To   time.Time `json:"to,omitempty"`
+	// IncludeICCIDs asks the API to return the ICCIDs of each line item
+	IncludeICCIDs bool `json:"includeIccids,omitempty"`
+}
+
+// List retrieves a page of the orders placed by the organisation
+func (s *OrdersService) List(ctx context.Context, req *ListOrdersRequest) (*Orders, error) {
+	if req == nil {
+		req = &ListOrdersRequest{}
+	}
+
+	params := url.Values{}
+	if req.Page > 0 {
+		params.Set("page", strconv.Itoa(req.Page))
+	}
+	if req.PerPage > 0 {
+		params.Set("perPage", strconv.Itoa(req.PerPage))
+	}
+	if !req.From.IsZero() {
+		params.Set("from", req.From.UTC().Format(time.RFC3339))
+	}
+	if !req.To.IsZero() {
+		params.Set("to", req.To.UTC().Format(time.RFC3339))
+	}
+	if req.IncludeICCIDs {
+		params.Set("includeIccids", "true")
+	}
+
+	endpoint := "/orders"
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	var resp Orders
+	err := s.client.makeRequest(ctx, "GET", endpoint, nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+	return &resp, nil
+}
+
+// ListAll returns an iterator over every order matching req, starting at
+// req.Page and fetching further pages lazily
+func (s *OrdersService) ListAll(ctx context.Context, req *ListOrdersRequest) *Iterator[Order] {
+	base := ListOrdersRequest{}
+	if req != nil {
+		base = *req
+	}
+
+	return newIterator(ctx, base.Page, func(ctx context.Context, page int) ([]Order, PageInfo, error) {
+		pageReq := base
+		pageReq.Page = page
+		resp, err := s.List(ctx, &pageReq)
+		if err != nil {
+			return nil, PageInfo{}, err
+		}
+		return resp.Orders, resp.PageInfo, nil
+	})
+}
+
+// Get retrieves an order by its reference
+func (s *OrdersService) Get(ctx context.Context, orderReference string) (*Order, error) {
+	var resp Order
+	err := s.client.makeRequest(ctx, "GET", "/orders/"+url.PathEscape(orderReference), nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,178 @@
+package esimgo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestOrderList(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/orders" {
+			t.Errorf("Expected GET /orders, got %s %s", r.Method, r.URL.Path)
+		}
+		gotQuery = r.URL.RawQuery
+		w.Write([]byte(`{"orders":[{"orderReference":"ord-1","status":"completed","statusMessage":"Order completed: 2 eSIMs assigned",` +
+			`"order":[{"type":"bundle","item":"esim_1GB_7D_GB_V2","quantity":2,"iccids":["8944500102198304820","8944500102198304821"],"subTotal":2.8,"pricePerUnit":1.4}],` +
+			`"total":2.8,"currency":"USD","createdDate":"2024-03-01T10:00:00Z","assigned":true}],"pageCount":1,"rows":1}`))
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+	resp, err := client.Orders.List(context.Background(), &ListOrdersRequest{
+		Page:          2,
+		PerPage:       50,
+		From:          time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:            time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		IncludeICCIDs: true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := "from=2024-03-01T00%3A00%3A00Z&includeIccids=true&page=2&perPage=50&to=2024-03-31T00%3A00%3A00Z"
+	if gotQuery != want {
+		t.Errorf("Expected query %s, got %s", want, gotQuery)
+	}
+	if len(resp.Orders) != 1 || resp.PageCount != 1 {
+		t.Fatalf("Unexpected response %+v", resp)
+	}
+	order := resp.Orders[0]
+	if order.OrderReference != "ord-1" || order.Status != "completed" || order.Total != NewMoney(280, "USD") {
+		t.Errorf("Unexpected order %+v", order)
+	}
+	if !order.CreatedDate.Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected created date %v", order.CreatedDate)
+	}
+	if iccids := order.ICCIDs(); len(iccids) != 2 || iccids[1] != "8944500102198304821" {
+		t.Errorf("Unexpected ICCIDs %v", iccids)
+	}
+}
+
+func TestOrderListAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Write([]byte(`{"orders":[{"orderReference":"ord-1"},{"orderReference":"ord-2"}],"pageCount":2}`))
+		case "2":
+			w.Write([]byte(`{"orders":[{"orderReference":"ord-3"}],"pageCount":2}`))
+		default:
+			t.Errorf("Unexpected page %s", r.URL.Query().Get("page"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+	orders, err := client.Orders.ListAll(context.Background(), nil).Collect()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(orders) != 3 || orders[2].OrderReference != "ord-3" {
+		t.Errorf("Unexpected orders %+v", orders)
+	}
+}
+
+func TestOrderGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.EscapedPath() != "/orders/ord%2F1" {
+			t.Errorf("Expected GET /orders/ord%%2F1, got %s %s", r.Method, r.URL.EscapedPath())
+		}
+		w.Write([]byte(`{"orderReference":"ord/1","status":"completed","order":[{"type":"bundle","item":"esim_1GB_7D_GB_V2","quantity":1,"iccids":["8944500102198304820"]}]}`))
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+	order, err := client.Orders.Get(context.Background(), "ord/1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if order.OrderReference != "ord/1" || len(order.Items) != 1 || order.Items[0].ICCIDs[0] != "8944500102198304820" {
+		t.Errorf("Unexpected order %+v", order)
+	}
+}
+
+func TestOrderCreateResponse(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		call  func(*OrdersService) (*CreateOrderResponse, error)
+		check func(*testing.T, *CreateOrderResponse)
+	}{
+		{
+			name: "transaction",
+			file: "testdata/order_transaction.json",
+			call: func(s *OrdersService) (*CreateOrderResponse, error) {
+				return s.Create(context.Background(), &CreateOrderRequest{
+					Type:   OrderTypeTransaction,
+					Assign: true,
+					Order:  []OrderItem{{Type: BundleTypeBundle, Quantity: 2, Item: "esim_1GB_7D_GB_V2"}},
+				})
+			},
+			check: func(t *testing.T, resp *CreateOrderResponse) {
+				if resp.OrderReference != "b3f7a5c2-1d4e-4f6a-9b8c-2e1f0d3c4b5a" || resp.Status != "completed" {
+					t.Errorf("Unexpected order %+v", resp)
+				}
+				if len(resp.Items) != 1 || resp.Items[0].PricePerUnit != NewMoney(140, "USD") || resp.Items[0].Quantity != 2 {
+					t.Errorf("Unexpected line items %+v", resp.Items)
+				}
+				if len(resp.ESIMs) != 2 || resp.ESIMs[1].MatchingID != "MNO12-PQR34-STU56-VWX78" || resp.ESIMs[1].SMDPAddress != "rsp.esim-go.com" {
+					t.Errorf("Unexpected eSIMs %+v", resp.ESIMs)
+				}
+				if iccids := resp.ICCIDs(); len(iccids) != 2 || iccids[0] != "8944500102198304820" {
+					t.Errorf("Unexpected ICCIDs %v", iccids)
+				}
+				if string(resp.Raw["sourceIP"]) != `"203.0.113.10"` {
+					t.Errorf("Expected unknown fields in Raw, got %s", resp.Raw["sourceIP"])
+				}
+			},
+		},
+		{
+			name: "validation",
+			file: "testdata/order_validate.json",
+			call: func(s *OrdersService) (*CreateOrderResponse, error) {
+				return s.Validate(context.Background(), []OrderItem{{Type: BundleTypeBundle, Quantity: 2, Item: "esim_1GB_7D_GB_V2"}}, false)
+			},
+			check: func(t *testing.T, resp *CreateOrderResponse) {
+				if !resp.Valid || resp.Total != NewMoney(280, "USD") || resp.Currency != "USD" || resp.OrderReference != "" {
+					t.Errorf("Unexpected validation %+v", resp)
+				}
+				if !resp.CreatedDate.Equal(time.Date(2024, 3, 1, 10, 15, 0, 0, time.UTC)) {
+					t.Errorf("Unexpected created date %v", resp.CreatedDate)
+				}
+				if len(resp.ICCIDs()) != 0 {
+					t.Errorf("Expected no ICCIDs, got %v", resp.ICCIDs())
+				}
+				if _, ok := resp.Raw["valid"]; !ok {
+					t.Error("Expected Raw to hold the modelled fields too")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := os.ReadFile(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "POST" || r.URL.Path != "/orders" {
+					t.Errorf("Expected POST /orders, got %s %s", r.Method, r.URL.Path)
+				}
+				w.Write(payload)
+			}))
+			defer server.Close()
+
+			client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+			resp, err := tt.call(client.Orders)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			tt.check(t, resp)
+		})
+	}
+}
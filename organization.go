@@ -2,6 +2,7 @@ package esimgo
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -30,8 +31,8 @@ type Organization struct {
 	Notes              string    `json:"notes"`
 	Groups             []string  `json:"groups"`
 	Currency           string    `json:"currency"`
-	Balance            int       `json:"balance"`
-	TestCredit         int       `json:"testCredit"`
+	Balance            Money     `json:"balance"`
+	TestCredit         Money     `json:"testCredit"`
 	TestCreditExpiry   time.Time `json:"testCreditExpiry"`
 	BusinessType       string    `json:"businessType"`
 	Website            string    `json:"website"`
@@ -39,6 +40,30 @@ type Organization struct {
 	Users              []User    `json:"users"`
 }
 
+// UnmarshalJSON implements json.Unmarshaler, decoding the balances in the
+// currency of the organisation
+func (o *Organization) UnmarshalJSON(data []byte) error {
+	type organization Organization
+	var decoded struct {
+		organization
+		Balance    json.RawMessage `json:"balance"`
+		TestCredit json.RawMessage `json:"testCredit"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*o = Organization(decoded.organization)
+	var err error
+	if o.Balance, err = decodeMoney(decoded.Balance, o.Currency); err != nil {
+		return fmt.Errorf("invalid balance: %w", err)
+	}
+	if o.TestCredit, err = decodeMoney(decoded.TestCredit, o.Currency); err != nil {
+		return fmt.Errorf("invalid testCredit: %w", err)
+	}
+	return nil
+}
+
 type Organizations struct {
 	Organizations []Organization `json:"organisations"`
 }
@@ -0,0 +1,138 @@
+package esimgo
+
+import "context"
+
+// PageInfo represents the pagination metadata returned by list endpoints
+type PageInfo struct {
+	PageCount int `json:"pageCount,omitempty"`
+	Rows      int `json:"rows,omitempty"`
+	PageSize  int `json:"pageSize,omitempty"`
+}
+
+// pageFetcher retrieves a single page of results
+type pageFetcher[T any] func(ctx context.Context, page int) ([]T, PageInfo, error)
+
+// Iterator lazily walks every page of a list endpoint.
+//
+// Pages are only requested when the items of the previous page have been
+// consumed, so callers can stop early simply by not calling Next again:
+//
+//	it := client.Catalogue.ListAll(ctx, &esimgo.ListCatalogueRequest{PerPage: 100})
+//	for it.Next() {
+//		bundle := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx   context.Context
+	fetch pageFetcher[T]
+	// keep, when set, skips the items it rejects without affecting paging
+	keep func(T) bool
+
+	page  int
+	items []T
+	index int
+	info  PageInfo
+
+	current T
+	err     error
+	done    bool
+}
+
+// newIterator creates an iterator that starts fetching at the given page
+func newIterator[T any](ctx context.Context, startPage int, fetch pageFetcher[T]) *Iterator[T] {
+	if startPage < 1 {
+		startPage = 1
+	}
+	return &Iterator[T]{
+		ctx:   ctx,
+		fetch: fetch,
+		page:  startPage - 1,
+	}
+}
+
+// Next advances the iterator, fetching the next page when needed. It returns
+// false once every page has been consumed, the context is done or a request
+// fails; use Err to tell these cases apart.
+func (it *Iterator[T]) Next() bool {
+	if it.done {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		it.done = true
+		return false
+	}
+
+	for {
+		for it.index >= len(it.items) {
+			if it.page > 0 && it.info.PageCount > 0 && it.page >= it.info.PageCount {
+				it.done = true
+				return false
+			}
+
+			items, info, err := it.fetch(it.ctx, it.page+1)
+			if err != nil {
+				it.err = err
+				it.done = true
+				return false
+			}
+			it.page++
+			it.items = items
+			it.index = 0
+			it.info = info
+
+			// Without page metadata an empty page is the only end marker
+			if len(items) == 0 && info.PageCount == 0 {
+				it.done = true
+				return false
+			}
+		}
+
+		item := it.items[it.index]
+		it.index++
+		if it.keep == nil || it.keep(item) {
+			it.current = item
+			return true
+		}
+	}
+}
+
+// filter makes the iterator skip the items rejected by keep. Pages are
+// still fetched whole, so a page whose items are all skipped is not taken
+// for the end of the data.
+func (it *Iterator[T]) filter(keep func(T) bool) *Iterator[T] {
+	it.keep = keep
+	return it
+}
+
+// Value returns the item the iterator is positioned on
+func (it *Iterator[T]) Value() T {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Page returns the number of the last page fetched
+func (it *Iterator[T]) Page() int {
+	return it.page
+}
+
+// PageInfo returns the pagination metadata of the last page fetched
+func (it *Iterator[T]) PageInfo() PageInfo {
+	return it.info
+}
+
+// Collect drains the iterator and returns every remaining item
+func (it *Iterator[T]) Collect() ([]T, error) {
+	var all []T
+	for it.Next() {
+		all = append(all, it.Value())
+	}
+	return all, it.Err()
+}
@@ -0,0 +1,167 @@
+package esimgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrOrderGuard reports that PlaceOrder rejected a quote before placing the
+// order
+var ErrOrderGuard = errors.New("esimgo: order rejected by guard")
+
+// Guards checked by PlaceOrder
+const (
+	GuardValid    = "valid"
+	GuardMaxTotal = "maxTotal"
+	GuardCurrency = "currency"
+	GuardBalance  = "balance"
+)
+
+// GuardError is returned when a quote fails one of the guards of
+// PlaceOrder. It matches ErrOrderGuard, and ErrInsufficientBalance when the
+// balance guard failed.
+type GuardError struct {
+	// Guard names the guard that failed, such as GuardMaxTotal
+	Guard   string
+	Message string
+	// Quote is the validation response the guard was checked against
+	Quote *CreateOrderResponse
+}
+
+func (e *GuardError) Error() string {
+	return fmt.Sprintf("order guard %s failed: %s", e.Guard, e.Message)
+}
+
+// Is reports whether the error matches ErrOrderGuard or, for the balance
+// guard, ErrInsufficientBalance
+func (e *GuardError) Is(target error) bool {
+	return target == ErrOrderGuard || (target == ErrInsufficientBalance && e.Guard == GuardBalance)
+}
+
+// OrderGuards are the conditions a quote must meet before PlaceOrder places
+// the order; zero values disable a guard
+type OrderGuards struct {
+	// MaxTotal is the highest total accepted. Without a currency it is
+	// taken to be in the currency of the quote; with one, quotes in another
+	// currency fail the currency guard.
+	MaxTotal Money
+	// Currency is the currency the quote must be in
+	Currency string
+	// CheckBalance requires the organisation balance to cover the total
+	CheckBalance bool
+}
+
+// PlaceOrderRequest represents an order placed with PlaceOrder
+type PlaceOrderRequest struct {
+	Items  []OrderItem
+	Assign bool
+	// IdempotencyKey identifies the transaction across retries; PlaceOrder
+	// generates one when it is empty and stores it back in the request
+	IdempotencyKey string
+	Guards         OrderGuards
+}
+
+// PlaceOrderResult holds the validation quote and the placed order
+type PlaceOrderResult struct {
+	Quote *CreateOrderResponse
+	Order *CreateOrderResponse
+}
+
+// PlaceOrder validates an order, checks the quote against req.Guards and
+// only then places it as a transaction. Nothing is bought when validation
+// or a guard fails; guard failures are reported as a *GuardError.
+func (s *OrdersService) PlaceOrder(ctx context.Context, req *PlaceOrderRequest) (*PlaceOrderResult, error) {
+	quote, err := s.Validate(ctx, req.Items, req.Assign)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place order: %w", err)
+	}
+	if err := s.checkGuards(ctx, req.Guards, quote); err != nil {
+		return nil, fmt.Errorf("failed to place order: %w", err)
+	}
+
+	createReq := &CreateOrderRequest{
+		Type:           OrderTypeTransaction,
+		Assign:         req.Assign,
+		Order:          req.Items,
+		IdempotencyKey: req.IdempotencyKey,
+	}
+	order, err := s.Create(ctx, createReq)
+	req.IdempotencyKey = createReq.IdempotencyKey
+	if err != nil {
+		return nil, fmt.Errorf("failed to place order: %w", err)
+	}
+	return &PlaceOrderResult{Quote: quote, Order: order}, nil
+}
+
+// checkGuards checks a quote against the guards
+func (s *OrdersService) checkGuards(ctx context.Context, guards OrderGuards, quote *CreateOrderResponse) error {
+	if !quote.Valid {
+		return &GuardError{Guard: GuardValid, Message: "order is not valid", Quote: quote}
+	}
+	if !guards.MaxTotal.IsZero() {
+		maxTotal := guards.MaxTotal
+		if maxTotal.Currency == "" {
+			converted, err := maxTotal.WithCurrency(quote.Total.Currency)
+			if err != nil {
+				return &GuardError{
+					Guard:   GuardCurrency,
+					Message: fmt.Sprintf("cannot compare total %s with %s: %v", quote.Total, guards.MaxTotal, err),
+					Quote:   quote,
+				}
+			}
+			maxTotal = converted
+		}
+		cmp, err := quote.Total.Cmp(maxTotal)
+		if err != nil {
+			return &GuardError{
+				Guard:   GuardCurrency,
+				Message: fmt.Sprintf("cannot compare total %s with %s: %v", quote.Total, guards.MaxTotal, err),
+				Quote:   quote,
+			}
+		}
+		if cmp > 0 {
+			return &GuardError{
+				Guard:   GuardMaxTotal,
+				Message: fmt.Sprintf("total %s exceeds %s", quote.Total, maxTotal),
+				Quote:   quote,
+			}
+		}
+	}
+	if guards.Currency != "" && !strings.EqualFold(guards.Currency, quote.Currency) {
+		return &GuardError{
+			Guard:   GuardCurrency,
+			Message: fmt.Sprintf("quote is in %s, expected %s", quote.Currency, guards.Currency),
+			Quote:   quote,
+		}
+	}
+	if !guards.CheckBalance {
+		return nil
+	}
+
+	orgs, err := NewOrganizationService(s.client).GetDetails(ctx)
+	if err != nil {
+		return err
+	}
+	if len(orgs.Organizations) == 0 {
+		return errors.New("no organisation returned to check the balance against")
+	}
+	org := orgs.Organizations[0]
+	cmp, err := quote.Total.Cmp(org.Balance)
+	if err != nil {
+		return &GuardError{
+			Guard:   GuardCurrency,
+			Message: fmt.Sprintf("balance is in %s but the quote is in %s", org.Balance.Currency, quote.Total.Currency),
+			Quote:   quote,
+		}
+	}
+	if cmp > 0 {
+		return &GuardError{
+			Guard:   GuardBalance,
+			Message: fmt.Sprintf("total %s exceeds balance %s", quote.Total, org.Balance),
+			Quote:   quote,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package esimgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOrderPlaceOrder(t *testing.T) {
+	tests := []struct {
+		name         string
+		quote        string
+		balance      string
+		guards       OrderGuards
+		wantGuard    string
+		wantBalance  bool
+		wantOrder    bool
+		wantSentinel error
+	}{
+		{
+			name:      "placed",
+			quote:     `{"valid":true,"total":2.8,"currency":"USD"}`,
+			guards:    OrderGuards{MaxTotal: NewMoney(500, "USD"), Currency: "usd", CheckBalance: true},
+			wantOrder: true,
+		},
+		{
+			name:      "invalid quote",
+			quote:     `{"valid":false,"total":2.8,"currency":"USD"}`,
+			wantGuard: GuardValid,
+		},
+		{
+			name:      "above max total",
+			quote:     `{"valid":true,"total":12.5,"currency":"USD"}`,
+			guards:    OrderGuards{MaxTotal: NewMoney(1000, "")},
+			wantGuard: GuardMaxTotal,
+		},
+		{
+			name:      "max total in another currency",
+			quote:     `{"valid":true,"total":2.8,"currency":"USD"}`,
+			guards:    OrderGuards{MaxTotal: NewMoney(1000, "EUR")},
+			wantGuard: GuardCurrency,
+		},
+		{
+			name:      "unexpected currency",
+			quote:     `{"valid":true,"total":2.8,"currency":"EUR"}`,
+			guards:    OrderGuards{Currency: "USD"},
+			wantGuard: GuardCurrency,
+		},
+		{
+			name:         "insufficient balance",
+			quote:        `{"valid":true,"total":120,"currency":"USD"}`,
+			balance:      `"currency":"USD","balance":100`,
+			guards:       OrderGuards{CheckBalance: true},
+			wantGuard:    GuardBalance,
+			wantBalance:  true,
+			wantSentinel: ErrInsufficientBalance,
+		},
+		{
+			name:         "balance below total by cents",
+			quote:        `{"valid":true,"total":2.8,"currency":"USD"}`,
+			balance:      `"currency":"USD","balance":2.79`,
+			guards:       OrderGuards{CheckBalance: true},
+			wantGuard:    GuardBalance,
+			wantBalance:  true,
+			wantSentinel: ErrInsufficientBalance,
+		},
+		{
+			name:        "balance in another currency",
+			quote:       `{"valid":true,"total":2.8,"currency":"USD"}`,
+			balance:     `"currency":"EUR","balance":100`,
+			guards:      OrderGuards{CheckBalance: true},
+			wantGuard:   GuardCurrency,
+			wantBalance: true,
+		},
+		{
+			name:      "balance covering the total",
+			quote:     `{"valid":true,"total":2.8,"currency":"USD"}`,
+			balance:   `"currency":"USD","balance":2.8`,
+			guards:    OrderGuards{CheckBalance: true},
+			wantOrder: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var transactions int
+			balanceChecked := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/organisation" {
+					balanceChecked = true
+					balance := tt.balance
+					if balance == "" {
+						balance = `"currency":"USD","balance":100`
+					}
+					w.Write([]byte(`{"organisations":[{"name":"Acme",` + balance + `}]}`))
+					return
+				}
+				var req CreateOrderRequest
+				json.NewDecoder(r.Body).Decode(&req)
+				switch req.Type {
+				case OrderTypeValidate:
+					w.Write([]byte(tt.quote))
+				case OrderTypeTransaction:
+					transactions++
+					if r.Header.Get("Idempotency-Key") == "" {
+						t.Error("Expected the transaction to carry an idempotency key")
+					}
+					w.Write([]byte(`{"orderReference":"ord-1","status":"completed","total":2.8,"currency":"USD"}`))
+				}
+			}))
+			defer server.Close()
+
+			client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+			req := &PlaceOrderRequest{
+				Items:  []OrderItem{{Type: BundleTypeBundle, Quantity: 2, Item: "esim_1GB_7D_GB_V2"}},
+				Assign: true,
+				Guards: tt.guards,
+			}
+			result, err := client.Orders.PlaceOrder(context.Background(), req)
+
+			if tt.wantOrder {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if result.Quote.Total != NewMoney(280, "USD") || result.Order.OrderReference != "ord-1" {
+					t.Errorf("Unexpected result %+v %+v", result.Quote, result.Order)
+				}
+				if transactions != 1 || !balanceChecked || req.IdempotencyKey == "" {
+					t.Errorf("Expected one transaction after a balance check, got %d (checked %v, key %q)",
+						transactions, balanceChecked, req.IdempotencyKey)
+				}
+				return
+			}
+
+			var guardErr *GuardError
+			if !errors.As(err, &guardErr) || !errors.Is(err, ErrOrderGuard) {
+				t.Fatalf("Expected a guard error, got %v", err)
+			}
+			if guardErr.Guard != tt.wantGuard || guardErr.Quote == nil {
+				t.Errorf("Expected guard %s with the quote, got %+v", tt.wantGuard, guardErr)
+			}
+			if tt.wantSentinel != nil && !errors.Is(err, tt.wantSentinel) {
+				t.Errorf("Expected %v, got %v", tt.wantSentinel, err)
+			}
+			if balanceChecked != tt.wantBalance {
+				t.Errorf("Expected balance checked %v, got %v", tt.wantBalance, balanceChecked)
+			}
+			if result != nil || transactions != 0 {
+				t.Errorf("Expected no order to be placed, got %d transactions", transactions)
+			}
+		})
+	}
+}
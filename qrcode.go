@@ -0,0 +1,654 @@
+// QR Code generator, ported to Go from the QR Code generator library by
+// Project Nayuki.
+//
+// Copyright (c) Project Nayuki. (MIT License)
+// https://www.nayuki.io/page/qr-code-generator-library
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+// - The above copyright notice and this permission notice shall be included in
+//   all copies or substantial portions of the Software.
+// - The Software is provided "as is", without warranty of any kind, express or
+//   implied, including but not limited to the warranties of merchantability,
+//   fitness for a particular purpose and noninfringement. In no event shall the
+//   authors or copyright holders be liable for any claim, damages or other
+//   liability, whether in an action of contract, tort or otherwise, arising from,
+//   out of or in connection with the Software or the use or other dealings in the
+//   Software.
+
+package esimgo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+)
+
+// QRErrorCorrection represents the error correction level of a QR code
+type QRErrorCorrection int
+
+// QR code error correction levels, from the least to the most redundant
+const (
+	QRErrorCorrectionLow      QRErrorCorrection = iota // recovers ~7% of the symbol
+	QRErrorCorrectionMedium                            // recovers ~15% of the symbol
+	QRErrorCorrectionQuartile                          // recovers ~25% of the symbol
+	QRErrorCorrectionHigh                              // recovers ~30% of the symbol
+)
+
+// ErrQRDataTooLong is returned when the data does not fit in a version 40
+// QR code at the requested error correction level
+var ErrQRDataTooLong = errors.New("esimgo: data too long for a QR code")
+
+const (
+	qrMinVersion = 1
+	qrMaxVersion = 40
+	// qrQuietZone is the light border, in modules, required around a symbol
+	qrQuietZone = 4
+)
+
+// formatBits returns the two bits identifying the level in format information
+func (l QRErrorCorrection) formatBits() int {
+	switch l {
+	case QRErrorCorrectionLow:
+		return 1
+	case QRErrorCorrectionMedium:
+		return 0
+	case QRErrorCorrectionQuartile:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// qrEccCodewordsPerBlock is indexed by error correction level and version
+var qrEccCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+// qrErrorCorrectionBlocks is indexed by error correction level and version
+var qrErrorCorrectionBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// QRCode represents a QR code symbol encoded in byte mode
+type QRCode struct {
+	version int
+	level   QRErrorCorrection
+	mask    int
+	size    int
+	// modules[y][x] is true for dark modules
+	modules [][]bool
+	// function marks the modules that are not data, such as finder patterns
+	function [][]bool
+}
+
+// NewQRCode encodes data as a QR code, choosing the smallest version that
+// fits at the requested error correction level
+func NewQRCode(data []byte, level QRErrorCorrection) (*QRCode, error) {
+	if level < QRErrorCorrectionLow || level > QRErrorCorrectionHigh {
+		return nil, fmt.Errorf("invalid QR error correction level %d", level)
+	}
+
+	version := 0
+	for v := qrMinVersion; v <= qrMaxVersion; v++ {
+		if qrDataBits(len(data), v) <= qrDataCodewords(v, level)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrQRDataTooLong
+	}
+
+	// Mode indicator, character count and payload
+	var bits qrBitBuffer
+	bits.append(0x4, 4)
+	bits.append(len(data), qrCharCountBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+
+	// Terminator, byte alignment and alternating pad bytes
+	capacity := qrDataCodewords(version, level) * 8
+	terminator := capacity - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bits.append(0, terminator)
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	codewords := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			codewords[i>>3] |= 1 << (7 - uint(i&7))
+		}
+	}
+
+	q := &QRCode{
+		version: version,
+		level:   level,
+		size:    version*4 + 17,
+	}
+	q.modules = make([][]bool, q.size)
+	q.function = make([][]bool, q.size)
+	for i := range q.modules {
+		q.modules[i] = make([]bool, q.size)
+		q.function[i] = make([]bool, q.size)
+	}
+
+	q.drawFunctionPatterns()
+	q.drawCodewords(q.addEccAndInterleave(codewords))
+
+	// Pick the mask with the lowest penalty
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormatBits(mask)
+		if penalty := q.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		q.applyMask(mask) // masks are their own inverse
+	}
+	q.mask = bestMask
+	q.applyMask(bestMask)
+	q.drawFormatBits(bestMask)
+
+	return q, nil
+}
+
+// Version returns the QR code version, between 1 and 40
+func (q *QRCode) Version() int {
+	return q.version
+}
+
+// Size returns the width and height of the symbol in modules, excluding
+// the quiet zone
+func (q *QRCode) Size() int {
+	return q.size
+}
+
+// Module reports whether the module at column x and row y is dark
+func (q *QRCode) Module(x, y int) bool {
+	return x >= 0 && x < q.size && y >= 0 && y < q.size && q.modules[y][x]
+}
+
+// Image renders the QR code with each module scale pixels wide, including
+// the quiet zone
+func (q *QRCode) Image(scale int) image.Image {
+	if scale < 1 {
+		scale = 1
+	}
+	width := (q.size + 2*qrQuietZone) * scale
+	img := image.NewPaletted(image.Rect(0, 0, width, width), color.Palette{color.White, color.Black})
+
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if !q.modules[y][x] {
+				continue
+			}
+			top := (y + qrQuietZone) * scale
+			left := (x + qrQuietZone) * scale
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetColorIndex(left+dx, top+dy, 1)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// PNG renders the QR code as a PNG image with each module scale pixels wide
+func (q *QRCode) PNG(scale int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, q.Image(scale)); err != nil {
+		return nil, fmt.Errorf("failed to encode QR code PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG renders the QR code as an SVG document with each module scale user
+// units wide
+func (q *QRCode) SVG(scale int) string {
+	if scale < 1 {
+		scale = 1
+	}
+	width := q.size + 2*qrQuietZone
+
+	var path strings.Builder
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if q.modules[y][x] {
+				fmt.Fprintf(&path, "M%d,%dh1v1h-1z", x+qrQuietZone, y+qrQuietZone)
+			}
+		}
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<svg xmlns="http://www.w3.org/2000/svg" version="1.1" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n"+
+		`<rect width="100%%" height="100%%" fill="#FFFFFF"/>`+"\n"+
+		`<path d="%s" fill="#000000"/>`+"\n"+
+		`</svg>`+"\n",
+		width*scale, width*scale, width, width, path.String())
+}
+
+// setFunction sets a function module, which data and masks never touch
+func (q *QRCode) setFunction(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.function[y][x] = true
+}
+
+func (q *QRCode) drawFunctionPatterns() {
+	// Timing patterns
+	for i := 0; i < q.size; i++ {
+		q.setFunction(6, i, i%2 == 0)
+		q.setFunction(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns and their separators
+	q.drawFinderPattern(3, 3)
+	q.drawFinderPattern(q.size-4, 3)
+	q.drawFinderPattern(3, q.size-4)
+
+	// Alignment patterns, skipping the ones that overlap finder patterns
+	positions := qrAlignmentPositions(q.version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			q.drawAlignmentPattern(x, y)
+		}
+	}
+
+	// Reserve the format areas with a placeholder, then draw the version
+	q.drawFormatBits(0)
+	q.drawVersion()
+}
+
+func (q *QRCode) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= q.size || yy < 0 || yy >= q.size {
+				continue
+			}
+			dist := max(absInt(dx), absInt(dy))
+			q.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (q *QRCode) drawAlignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			q.setFunction(x+dx, y+dy, max(absInt(dx), absInt(dy)) != 1)
+		}
+	}
+}
+
+// drawFormatBits draws both copies of the format information
+func (q *QRCode) drawFormatBits(mask int) {
+	bits := qrFormatBits(q.level, mask)
+
+	// Copy around the top left finder pattern
+	for i := 0; i <= 5; i++ {
+		q.setFunction(8, i, qrBit(bits, i))
+	}
+	q.setFunction(8, 7, qrBit(bits, 6))
+	q.setFunction(8, 8, qrBit(bits, 7))
+	q.setFunction(7, 8, qrBit(bits, 8))
+	for i := 9; i < 15; i++ {
+		q.setFunction(14-i, 8, qrBit(bits, i))
+	}
+
+	// Copy split between the other two finder patterns
+	for i := 0; i < 8; i++ {
+		q.setFunction(q.size-1-i, 8, qrBit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunction(8, q.size-15+i, qrBit(bits, i))
+	}
+	q.setFunction(8, q.size-8, true) // always dark
+}
+
+// drawVersion draws both copies of the version information (version 7+)
+func (q *QRCode) drawVersion() {
+	if q.version < 7 {
+		return
+	}
+	bits := qrVersionBits(q.version)
+	for i := 0; i < 18; i++ {
+		bit := qrBit(bits, i)
+		a, b := q.size-11+i%3, i/3
+		q.setFunction(a, b, bit)
+		q.setFunction(b, a, bit)
+	}
+}
+
+// addEccAndInterleave splits the data into blocks, appends the error
+// correction codewords of each block and interleaves the result
+func (q *QRCode) addEccAndInterleave(data []byte) []byte {
+	numBlocks := qrErrorCorrectionBlocks[q.level][q.version]
+	blockEccLen := qrEccCodewordsPerBlock[q.level][q.version]
+	rawCodewords := qrRawDataModules(q.version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := reedSolomonDivisor(blockEccLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		dataLen := shortBlockLen - blockEccLen
+		if i >= numShortBlocks {
+			dataLen++
+		}
+		block := make([]byte, 0, shortBlockLen+1)
+		block = append(block, data[k:k+dataLen]...)
+		k += dataLen
+		ecc := reedSolomonRemainder(block, divisor)
+		if i < numShortBlocks {
+			// Placeholder keeping short and long blocks aligned
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-blockEccLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// drawCodewords places the codewords in the zigzag order used by QR codes
+func (q *QRCode) drawCodewords(data []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // skip the vertical timing pattern
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				upward := (right+1)&2 == 0
+				y := vert
+				if upward {
+					y = q.size - 1 - vert
+				}
+				if !q.function[y][x] && i < len(data)*8 {
+					q.modules[y][x] = qrBit(int(data[i>>3]), 7-(i&7))
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask XORs the data modules with the given mask pattern
+func (q *QRCode) applyMask(mask int) {
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !q.function[y][x] {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol with the four rules of the QR specification;
+// lower is better
+func (q *QRCode) penalty() int {
+	const (
+		penaltyRun     = 3
+		penaltyBlock   = 3
+		penaltyFinder  = 40
+		penaltyBalance = 10
+	)
+	finderLike := [][]bool{
+		{true, false, true, true, true, false, true, false, false, false, false},
+		{false, false, false, false, true, false, true, true, true, false, true},
+	}
+
+	result := 0
+	line := make([]bool, q.size)
+	for axis := 0; axis < 2; axis++ {
+		for i := 0; i < q.size; i++ {
+			for j := 0; j < q.size; j++ {
+				if axis == 0 {
+					line[j] = q.modules[i][j]
+				} else {
+					line[j] = q.modules[j][i]
+				}
+			}
+
+			// Rule 1: runs of five or more modules of the same colour
+			run := 1
+			for j := 1; j <= q.size; j++ {
+				if j < q.size && line[j] == line[j-1] {
+					run++
+					continue
+				}
+				if run >= 5 {
+					result += penaltyRun + run - 5
+				}
+				run = 1
+			}
+
+			// Rule 3: patterns resembling finder patterns
+			for j := 0; j+11 <= q.size; j++ {
+				for _, pattern := range finderLike {
+					match := true
+					for k, dark := range pattern {
+						if line[j+k] != dark {
+							match = false
+							break
+						}
+					}
+					if match {
+						result += penaltyFinder
+					}
+				}
+			}
+		}
+	}
+
+	// Rule 2: 2x2 blocks of the same colour
+	for y := 0; y < q.size-1; y++ {
+		for x := 0; x < q.size-1; x++ {
+			c := q.modules[y][x]
+			if c == q.modules[y][x+1] && c == q.modules[y+1][x] && c == q.modules[y+1][x+1] {
+				result += penaltyBlock
+			}
+		}
+	}
+
+	// Rule 4: balance of dark and light modules
+	dark := 0
+	for _, row := range q.modules {
+		for _, module := range row {
+			if module {
+				dark++
+			}
+		}
+	}
+	total := q.size * q.size
+	k := (absInt(dark*20-total*10)+total-1)/total - 1
+	result += k * penaltyBalance
+
+	return result
+}
+
+// qrFormatBits returns the 15 format information bits, BCH encoded and masked
+func qrFormatBits(level QRErrorCorrection, mask int) int {
+	data := level.formatBits()<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// qrVersionBits returns the 18 version information bits, BCH encoded
+func qrVersionBits(version int) int {
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	return version<<12 | rem
+}
+
+// qrAlignmentPositions returns the centre coordinates of the alignment
+// patterns of a version
+func qrAlignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	numAlign := version/7 + 2
+	step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+	positions := make([]int, numAlign)
+	positions[0] = 6
+	for i, pos := numAlign-1, version*4+17-7; i >= 1; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+// qrRawDataModules returns the number of modules available for data and
+// error correction in a version
+func qrRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+// qrDataCodewords returns the number of data codewords of a version and level
+func qrDataCodewords(version int, level QRErrorCorrection) int {
+	return qrRawDataModules(version)/8 -
+		qrEccCodewordsPerBlock[level][version]*qrErrorCorrectionBlocks[level][version]
+}
+
+// qrCharCountBits returns the width of the byte mode character count
+func qrCharCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// qrDataBits returns the number of bits needed to encode n bytes
+func qrDataBits(n, version int) int {
+	countBits := qrCharCountBits(version)
+	if n >= 1<<uint(countBits) {
+		return 1 << 30
+	}
+	return 4 + countBits + n*8
+}
+
+// reedSolomonDivisor returns the generator polynomial of the given degree,
+// without its leading coefficient
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// reedSolomonRemainder returns the error correction codewords of data
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coef := range divisor {
+			result[i] ^= gfMultiply(coef, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies two elements of GF(2^8) modulo x^8+x^4+x^3+x^2+1
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+	return byte(z)
+}
+
+// qrBitBuffer accumulates bits, most significant first
+type qrBitBuffer []bool
+
+func (b *qrBitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, (value>>uint(i))&1 != 0)
+	}
+}
+
+func qrBit(value, i int) bool {
+	return (value>>uint(i))&1 != 0
+}
+
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,326 @@
+package esimgo
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestReedSolomon(t *testing.T) {
+	// "HELLO WORLD" at version 1-M, from the QR code specification examples
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	if got := reedSolomonRemainder(data, reedSolomonDivisor(10)); !bytes.Equal(got, want) {
+		t.Errorf("Expected error correction codewords %v, got %v", want, got)
+	}
+}
+
+func TestQRFormatAndVersionBits(t *testing.T) {
+	formats := []struct {
+		level QRErrorCorrection
+		mask  int
+		want  string
+	}{
+		{QRErrorCorrectionLow, 0, "111011111000100"},
+		{QRErrorCorrectionLow, 4, "110011000101111"},
+		{QRErrorCorrectionLow, 7, "110100101110110"},
+		{QRErrorCorrectionMedium, 0, "101010000010010"},
+		{QRErrorCorrectionQuartile, 0, "011010101011111"},
+		{QRErrorCorrectionHigh, 0, "001011010001001"},
+	}
+	for _, tt := range formats {
+		if got := strconv.FormatInt(int64(qrFormatBits(tt.level, tt.mask)), 2); leftPad(got, 15) != tt.want {
+			t.Errorf("qrFormatBits(%d, %d) = %s, expected %s", tt.level, tt.mask, leftPad(got, 15), tt.want)
+		}
+	}
+
+	versions := map[int]string{
+		7:  "000111110010010100",
+		21: "010101011010000011",
+		40: "101000110001101001",
+	}
+	for version, want := range versions {
+		if got := strconv.FormatInt(int64(qrVersionBits(version)), 2); leftPad(got, 18) != want {
+			t.Errorf("qrVersionBits(%d) = %s, expected %s", version, leftPad(got, 18), want)
+		}
+	}
+}
+
+func leftPad(s string, width int) string {
+	return strings.Repeat("0", width-len(s)) + s
+}
+
+func TestQRTables(t *testing.T) {
+	alignments := map[int][]int{
+		1:  nil,
+		2:  {6, 18},
+		7:  {6, 22, 38},
+		32: {6, 34, 60, 86, 112, 138},
+		40: {6, 30, 58, 86, 114, 142, 170},
+	}
+	for version, want := range alignments {
+		got := qrAlignmentPositions(version)
+		if len(got) != len(want) {
+			t.Errorf("qrAlignmentPositions(%d) = %v, expected %v", version, got, want)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("qrAlignmentPositions(%d) = %v, expected %v", version, got, want)
+				break
+			}
+		}
+	}
+
+	// Byte mode capacities from the QR code specification
+	capacities := []struct {
+		version int
+		level   QRErrorCorrection
+		bytes   int
+	}{
+		{1, QRErrorCorrectionLow, 17},
+		{1, QRErrorCorrectionMedium, 14},
+		{1, QRErrorCorrectionQuartile, 11},
+		{1, QRErrorCorrectionHigh, 7},
+		{10, QRErrorCorrectionMedium, 213},
+		{40, QRErrorCorrectionLow, 2953},
+		{40, QRErrorCorrectionHigh, 1273},
+	}
+	for _, tt := range capacities {
+		capacity := qrDataCodewords(tt.version, tt.level) * 8
+		if qrDataBits(tt.bytes, tt.version) > capacity || qrDataBits(tt.bytes+1, tt.version) <= capacity {
+			t.Errorf("Expected version %d level %d to hold exactly %d bytes", tt.version, tt.level, tt.bytes)
+		}
+	}
+}
+
+func TestNewQRCodeRoundTrip(t *testing.T) {
+	inputs := []struct {
+		data    string
+		level   QRErrorCorrection
+		version int
+	}{
+		{"HELLO", QRErrorCorrectionHigh, 1},
+		{"LPA:1$smdp.io$K2-1WE6FV-1GHJ4YQ", QRErrorCorrectionMedium, 3},
+		{"LPA:1$rsp.truphone.com$QRF-BETTERROAMING-PMRDGIR2EARDEIT5", QRErrorCorrectionMedium, 4},
+		{strings.Repeat("0123456789", 30), QRErrorCorrectionQuartile, 16},
+		{strings.Repeat("eSIM Go ", 200), QRErrorCorrectionLow, 29},
+	}
+
+	for _, tt := range inputs {
+		q, err := NewQRCode([]byte(tt.data), tt.level)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if q.Version() != tt.version {
+			t.Errorf("Expected version %d for %d bytes, got %d", tt.version, len(tt.data), q.Version())
+		}
+		if q.Size() != tt.version*4+17 {
+			t.Errorf("Expected size %d, got %d", tt.version*4+17, q.Size())
+		}
+
+		decoded, err := decodeQRForTest(q)
+		if err != nil {
+			t.Fatalf("Failed to decode version %d symbol: %v", q.Version(), err)
+		}
+		if string(decoded) != tt.data {
+			t.Errorf("Expected decoded data %q, got %q", tt.data, decoded)
+		}
+	}
+}
+
+func TestNewQRCodeTooLong(t *testing.T) {
+	if _, err := NewQRCode(make([]byte, 2954), QRErrorCorrectionLow); !errors.Is(err, ErrQRDataTooLong) {
+		t.Errorf("Expected ErrQRDataTooLong, got %v", err)
+	}
+	if _, err := NewQRCode([]byte("x"), QRErrorCorrection(9)); err == nil {
+		t.Error("Expected an error for an invalid level")
+	}
+}
+
+func TestQRCodeRendering(t *testing.T) {
+	q, err := NewQRCode([]byte("LPA:1$smdp.io$K2-1WE6FV-1GHJ4YQ"), QRErrorCorrectionMedium)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data, err := q.PNG(4)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Expected a valid PNG, got %v", err)
+	}
+	width := (q.Size() + 2*qrQuietZone) * 4
+	if img.Bounds().Dx() != width || img.Bounds().Dy() != width {
+		t.Errorf("Expected a %dx%d image, got %v", width, width, img.Bounds())
+	}
+	// The top left module of the finder pattern is dark, the quiet zone light
+	if r, _, _, _ := img.At(qrQuietZone*4, qrQuietZone*4).RGBA(); r != 0 {
+		t.Error("Expected the finder pattern corner to be dark")
+	}
+	if r, _, _, _ := img.At(0, 0).RGBA(); r == 0 {
+		t.Error("Expected the quiet zone to be light")
+	}
+
+	svg := q.SVG(4)
+	if !strings.HasPrefix(svg, "<?xml") || !strings.Contains(svg, "<svg") {
+		t.Errorf("Expected an SVG document, got %q", svg[:40])
+	}
+	if !strings.Contains(svg, `width="`+strconv.Itoa(width)+`"`) {
+		t.Errorf("Expected width %d in SVG", width)
+	}
+	if !strings.Contains(svg, "M4,4h1v1h-1z") {
+		t.Error("Expected the finder pattern corner in the SVG path")
+	}
+}
+
+// decodeQRForTest reads a symbol back following the QR specification:
+// format information, unmasking, codeword placement, de-interleaving,
+// error correction check and byte mode parsing
+func decodeQRForTest(q *QRCode) ([]byte, error) {
+	// Format information around the top left finder pattern
+	format := 0
+	for i := 0; i <= 5; i++ {
+		format |= boolBit(q.Module(8, i)) << i
+	}
+	format |= boolBit(q.Module(8, 7)) << 6
+	format |= boolBit(q.Module(8, 8)) << 7
+	format |= boolBit(q.Module(7, 8)) << 8
+	for i := 9; i < 15; i++ {
+		format |= boolBit(q.Module(14-i, 8)) << i
+	}
+	mask := -1
+	for m := 0; m < 8; m++ {
+		if qrFormatBits(q.level, m) == format {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		return nil, errors.New("format information does not match the level")
+	}
+
+	// Rebuild the function pattern map independently from the symbol
+	ref := &QRCode{version: q.version, level: q.level, size: q.size}
+	ref.modules = make([][]bool, ref.size)
+	ref.function = make([][]bool, ref.size)
+	for i := range ref.modules {
+		ref.modules[i] = make([]bool, ref.size)
+		ref.function[i] = make([]bool, ref.size)
+	}
+	ref.drawFunctionPatterns()
+
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if ref.function[y][x] {
+				if ref.modules[y][x] != q.modules[y][x] && !isFormatModule(q.size, x, y) {
+					return nil, errors.New("function pattern mismatch")
+				}
+				continue
+			}
+			ref.modules[y][x] = q.modules[y][x]
+		}
+	}
+	ref.applyMask(mask)
+
+	// Read codewords in placement order
+	raw := make([]byte, qrRawDataModules(q.version)/8)
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = q.size - 1 - vert
+				}
+				if !ref.function[y][x] && i < len(raw)*8 {
+					if ref.modules[y][x] {
+						raw[i>>3] |= 1 << (7 - uint(i&7))
+					}
+					i++
+				}
+			}
+		}
+	}
+
+	// De-interleave and verify each block
+	numBlocks := qrErrorCorrectionBlocks[q.level][q.version]
+	eccLen := qrEccCodewordsPerBlock[q.level][q.version]
+	numShort := numBlocks - len(raw)%numBlocks
+	shortData := len(raw)/numBlocks - eccLen
+
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for pos := 0; pos < shortData+1; pos++ {
+		for b := 0; b < numBlocks; b++ {
+			if pos == shortData && b < numShort {
+				continue
+			}
+			blocks[b] = append(blocks[b], raw[k])
+			k++
+		}
+	}
+	eccs := make([][]byte, numBlocks)
+	for pos := 0; pos < eccLen; pos++ {
+		for b := 0; b < numBlocks; b++ {
+			eccs[b] = append(eccs[b], raw[k])
+			k++
+		}
+	}
+
+	var data []byte
+	divisor := reedSolomonDivisor(eccLen)
+	for b := range blocks {
+		if !bytes.Equal(reedSolomonRemainder(blocks[b], divisor), eccs[b]) {
+			return nil, errors.New("error correction mismatch")
+		}
+		data = append(data, blocks[b]...)
+	}
+
+	// Byte mode segment
+	reader := bitReaderForTest{data: data}
+	if mode := reader.read(4); mode != 0x4 {
+		return nil, errors.New("expected byte mode")
+	}
+	count := reader.read(qrCharCountBits(q.version))
+	out := make([]byte, count)
+	for i := range out {
+		out[i] = byte(reader.read(8))
+	}
+	return out, nil
+}
+
+func isFormatModule(size, x, y int) bool {
+	return (x == 8 && (y < 9 || y >= size-8)) || (y == 8 && (x < 9 || x >= size-8))
+}
+
+func boolBit(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type bitReaderForTest struct {
+	data []byte
+	pos  int
+}
+
+func (r *bitReaderForTest) read(n int) int {
+	v := 0
+	for i := 0; i < n; i++ {
+		bit := (r.data[r.pos>>3] >> (7 - uint(r.pos&7))) & 1
+		v = v<<1 | int(bit)
+		r.pos++
+	}
+	return v
+}
@@ -0,0 +1,212 @@
+package esimgo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit describes a token bucket budget
+type RateLimit struct {
+	// Rate is the number of requests allowed per second; zero means unlimited
+	Rate float64
+	// Burst is the number of requests that may be sent at once (at least 1)
+	Burst int
+}
+
+// RateLimiter throttles the requests sent by a Client using token buckets.
+//
+// Every request consumes a token from the global budget and from the budget
+// of its endpoint class, which is the first segment of the endpoint path
+// ("esims", "orders", "catalogue", ...). When the API reports that the
+// budget is exhausted, through a 429 response or rate-limit headers, the
+// limiter pauses until the reported reset time.
+type RateLimiter struct {
+	mu          sync.Mutex
+	global      *tokenBucket
+	classes     map[string]*tokenBucket
+	pausedUntil time.Time
+	now         func() time.Time
+}
+
+// NewRateLimiter creates a rate limiter with the given global budget
+func NewRateLimiter(global RateLimit) *RateLimiter {
+	l := &RateLimiter{
+		classes: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+	l.global = newTokenBucket(global, l.now())
+	return l
+}
+
+// SetClassLimit sets the budget of an endpoint class, such as "esims"
+func (l *RateLimiter) SetClassLimit(class string, limit RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.classes[class] = newTokenBucket(limit, l.now())
+}
+
+// Wait blocks until a request to the given endpoint class may be sent. It
+// fails straight away when the wait would outlast the context deadline.
+func (l *RateLimiter) Wait(ctx context.Context, class string) error {
+	l.mu.Lock()
+	now := l.now()
+	classBucket := l.classes[class]
+
+	wait := l.pausedUntil.Sub(now)
+	if d := l.global.reserve(now); d > wait {
+		wait = d
+	}
+	if d := classBucket.reserve(now); d > wait {
+		wait = d
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && wait > 0 && now.Add(wait).After(deadline) {
+		l.global.release()
+		classBucket.release()
+		l.mu.Unlock()
+		return fmt.Errorf("rate limit wait of %v exceeds context deadline: %w", wait, context.DeadlineExceeded)
+	}
+	l.mu.Unlock()
+
+	if err := sleep(ctx, wait); err != nil {
+		l.mu.Lock()
+		l.global.release()
+		classBucket.release()
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Observe adapts the limiter to the rate-limit information of a response
+func (l *RateLimiter) Observe(class string, statusCode int, header http.Header) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var pause time.Duration
+	limited := statusCode == http.StatusTooManyRequests
+
+	if wait, ok := parseRetryAfter(header, now); ok && limited {
+		pause = wait
+	}
+	if remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining")); err == nil && remaining <= 0 {
+		limited = true
+		if reset, ok := parseRateLimitReset(header.Get("X-RateLimit-Reset"), now); ok && reset > pause {
+			pause = reset
+		}
+	}
+	if !limited {
+		return
+	}
+	if pause == 0 {
+		pause = time.Second
+	}
+
+	if until := now.Add(pause); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	l.global.drain(now)
+	l.classes[class].drain(now)
+}
+
+// endpointClass returns the rate limit class of an endpoint
+func endpointClass(endpoint string) string {
+	endpoint = strings.TrimPrefix(endpoint, "/")
+	if i := strings.IndexAny(endpoint, "/?"); i >= 0 {
+		endpoint = endpoint[:i]
+	}
+	return endpoint
+}
+
+// parseRateLimitReset reads an X-RateLimit-Reset header, expressed either
+// as seconds until the reset or as a Unix timestamp
+func parseRateLimitReset(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	// Values this large can only be Unix timestamps
+	if seconds > 1e9 {
+		reset := time.Unix(0, int64(seconds*float64(time.Second)))
+		if wait := reset.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
+
+// tokenBucket is a token bucket that hands out reservations; a nil bucket
+// never throttles
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit, now time.Time) *tokenBucket {
+	if limit.Rate <= 0 {
+		return nil
+	}
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   limit.Rate,
+		burst:  burst,
+		tokens: burst,
+		last:   now,
+	}
+}
+
+// advance refills the bucket up to now
+func (b *tokenBucket) advance(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// reserve takes a token and returns how long to wait before using it
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.advance(now)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// release gives back a reserved token that was not used
+func (b *tokenBucket) release() {
+	if b == nil {
+		return
+	}
+	b.tokens = math.Min(b.burst, b.tokens+1)
+}
+
+// drain empties the bucket so that no burst follows a rate-limit response
+func (b *tokenBucket) drain(now time.Time) {
+	if b == nil {
+		return
+	}
+	b.advance(now)
+	if b.tokens > 0 {
+		b.tokens = 0
+	}
+}
@@ -0,0 +1,146 @@
+package esimgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurstAndRefill(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{Rate: 50, Burst: 2})
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(ctx, "esims"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 15*time.Millisecond {
+		t.Errorf("Expected the burst to pass immediately, took %v", elapsed)
+	}
+
+	if err := limiter.Wait(ctx, "esims"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("Expected the third request to wait for a refill, took %v", elapsed)
+	}
+}
+
+func TestRateLimiterClassBudget(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{})
+	limiter.SetClassLimit("orders", RateLimit{Rate: 1, Burst: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx, "orders"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// Other classes are only bound by the unlimited global budget
+	for i := 0; i < 10; i++ {
+		if err := limiter.Wait(ctx, "catalogue"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	start := time.Now()
+	err := limiter.Wait(ctx, "orders")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("Expected the limiter to fail fast, took %v", elapsed)
+	}
+}
+
+func TestRateLimiterObserve(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		statusCode int
+		header     http.Header
+		wantPause  time.Duration
+	}{
+		{"ok response", http.StatusOK, http.Header{}, 0},
+		{"429 without headers", http.StatusTooManyRequests, http.Header{}, time.Second},
+		{"429 with Retry-After", http.StatusTooManyRequests, http.Header{"Retry-After": []string{"7"}}, 7 * time.Second},
+		{"remaining budget", http.StatusOK, http.Header{"X-Ratelimit-Remaining": []string{"3"}, "X-Ratelimit-Reset": []string{"30"}}, 0},
+		{"exhausted with reset delta", http.StatusOK, http.Header{"X-Ratelimit-Remaining": []string{"0"}, "X-Ratelimit-Reset": []string{"30"}}, 30 * time.Second},
+		{"exhausted with reset timestamp", http.StatusOK, http.Header{
+			"X-Ratelimit-Remaining": []string{"0"},
+			"X-Ratelimit-Reset":     []string{strconv.FormatInt(now.Add(12*time.Second).Unix(), 10)},
+		}, 12 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewRateLimiter(RateLimit{Rate: 10, Burst: 5})
+			limiter.now = func() time.Time { return now }
+
+			limiter.Observe("esims", tt.statusCode, tt.header)
+
+			var got time.Duration
+			if !limiter.pausedUntil.IsZero() {
+				got = limiter.pausedUntil.Sub(now)
+			}
+			if got != tt.wantPause {
+				t.Errorf("Expected pause of %v, got %v", tt.wantPause, got)
+			}
+		})
+	}
+}
+
+func TestClientRateLimiter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-api-key",
+		WithBaseURL(server.URL),
+		WithRateLimiter(NewRateLimiter(RateLimit{Rate: 100, Burst: 10})),
+	)
+
+	if err := client.makeRequest(context.Background(), "GET", "/esims", nil, nil); err == nil {
+		t.Fatal("Expected the rate-limited request to fail")
+	}
+
+	// The limiter now pauses for the Retry-After period
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := client.makeRequest(ctx, "GET", "/catalogue", nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected 1 call to reach the server, got %d", got)
+	}
+}
+
+func TestEndpointClass(t *testing.T) {
+	for endpoint, want := range map[string]string{
+		"/esims/apply":           "esims",
+		"/esims/8944123/bundles": "esims",
+		"/catalogue?page=2":      "catalogue",
+		"/orders":                "orders",
+		"":                       "",
+	} {
+		if got := endpointClass(endpoint); got != want {
+			t.Errorf("endpointClass(%q) = %q, expected %q", endpoint, got, want)
+		}
+	}
+}
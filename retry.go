@@ -0,0 +1,193 @@
+package esimgo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried.
+//
+// Only GET and HEAD requests are retried by default. Requests with side
+// effects are retried only when their context has been marked with
+// MarkRetrySafe, for example when the caller knows a POST is idempotent.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts, including the delays asked
+	// for by Retry-After headers
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after every attempt (defaults to 2)
+	Multiplier float64
+	// Jitter randomises each delay by up to this fraction (0 to 1)
+	Jitter float64
+	// RetryableStatusCodes lists the response codes worth retrying; when
+	// empty 429, 502, 503 and 504 are retried
+	RetryableStatusCodes []int
+}
+
+// DefaultRetryPolicy returns a policy suitable for most workloads
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+type retrySafeKey struct{}
+
+// MarkRetrySafe returns a context that allows requests with side effects,
+// such as POSTs, to be retried by the client's RetryPolicy
+func MarkRetrySafe(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retrySafeKey{}, true)
+}
+
+// isRetrySafe reports whether a request may be sent more than once
+func isRetrySafe(ctx context.Context, method string) bool {
+	if method == http.MethodGet || method == http.MethodHead {
+		return true
+	}
+	safe, _ := ctx.Value(retrySafeKey{}).(bool)
+	return safe
+}
+
+// attempts returns the number of attempts allowed for a request
+func (p *RetryPolicy) attempts(ctx context.Context, method string) int {
+	if p == nil || p.MaxAttempts < 1 || !isRetrySafe(ctx, method) {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// maxRetryAfter caps Retry-After delays when the policy has no MaxBackoff
+const maxRetryAfter = time.Minute
+
+// shouldRetry reports whether an attempt that ended with the given status
+// code or transport error is worth retrying. Only network errors are
+// retried; errors raised before the request was sent are not.
+func (p *RetryPolicy) shouldRetry(ctx context.Context, statusCode int, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return isNetworkError(err)
+	}
+
+	codes := p.RetryableStatusCodes
+	if len(codes) == 0 {
+		codes = defaultRetryableStatusCodes
+	}
+	for _, code := range codes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// isNetworkError reports whether err was raised while talking to the
+// server, as opposed to while preparing the request. Both kinds come
+// wrapped in a *url.Error, which is itself a net.Error, so the check looks
+// at the error it wraps.
+func isNetworkError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// delay returns how long to wait before the given retry (1 for the first),
+// honouring a Retry-After header when the server sent one, up to MaxBackoff
+func (p *RetryPolicy) delay(retry int, header http.Header) time.Duration {
+	if wait, ok := parseRetryAfter(header, time.Now()); ok {
+		limit := p.MaxBackoff
+		if limit <= 0 {
+			limit = maxRetryAfter
+		}
+		return min(wait, limit)
+	}
+
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+	wait := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		wait += wait * jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(wait)
+}
+
+// parseRetryAfter reads a Retry-After header expressed either in seconds or
+// as an HTTP date
+func parseRetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,279 @@
+package esimgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// newFlakyServer returns a server that answers the first failures requests
+// with the given status code and succeeds afterwards
+func newFlakyServer(t *testing.T, failures int32, statusCode int, header http.Header) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			for key, values := range header {
+				for _, value := range values {
+					w.Header().Add(key, value)
+				}
+			}
+			w.WriteHeader(statusCode)
+			json.NewEncoder(w).Encode(map[string]string{"message": "try again"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func testRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   int32
+		statusCode int
+		method     string
+		retrySafe  bool
+		wantErr    bool
+		wantCalls  int32
+	}{
+		{"GET recovers after 503", 2, http.StatusServiceUnavailable, "GET", false, false, 3},
+		{"GET recovers after 429", 1, http.StatusTooManyRequests, "GET", false, false, 2},
+		{"GET gives up after max attempts", 5, http.StatusBadGateway, "GET", false, true, 3},
+		{"GET does not retry 400", 1, http.StatusBadRequest, "GET", false, true, 1},
+		{"POST is not retried by default", 1, http.StatusServiceUnavailable, "POST", false, true, 1},
+		{"POST marked safe is retried", 1, http.StatusServiceUnavailable, "POST", true, false, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := newFlakyServer(t, tt.failures, tt.statusCode, nil)
+
+			client := NewClient("test-api-key", WithBaseURL(server.URL), WithRetryPolicy(testRetryPolicy()))
+
+			ctx := context.Background()
+			if tt.retrySafe {
+				ctx = MarkRetrySafe(ctx)
+			}
+
+			var body interface{}
+			if tt.method == "POST" {
+				body = map[string]string{"type": "validate"}
+			}
+
+			var result map[string]string
+			err := client.makeRequest(ctx, tt.method, "/test", body, &result)
+			if tt.wantErr && err == nil {
+				t.Error("Expected an error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if got := atomic.LoadInt32(calls); got != tt.wantCalls {
+				t.Errorf("Expected %d calls, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDisabledByDefault(t *testing.T) {
+	server, calls := newFlakyServer(t, 1, http.StatusServiceUnavailable, nil)
+
+	client := NewClient("test-api-key", WithBaseURL(server.URL))
+
+	if err := client.makeRequest(context.Background(), "GET", "/test", nil, nil); err == nil {
+		t.Error("Expected an error, got nil")
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("Expected 1 call, got %d", got)
+	}
+}
+
+func TestRetryPolicyHonoursRetryAfter(t *testing.T) {
+	header := http.Header{"Retry-After": []string{"1"}}
+	server, calls := newFlakyServer(t, 1, http.StatusTooManyRequests, header)
+
+	policy := testRetryPolicy()
+	policy.MaxBackoff = time.Minute
+	client := NewClient("test-api-key", WithBaseURL(server.URL), WithRetryPolicy(policy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := client.makeRequest(ctx, "GET", "/test", nil, nil)
+	if err == nil {
+		t.Fatal("Expected the context deadline to interrupt the Retry-After wait")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the wait to stop at the context deadline, took %v", elapsed)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("Expected 1 call, got %d", got)
+	}
+}
+
+func TestRetryPolicyCapsRetryAfter(t *testing.T) {
+	header := http.Header{"Retry-After": []string{"3600"}}
+	server, calls := newFlakyServer(t, 1, http.StatusTooManyRequests, header)
+
+	client := NewClient("test-api-key", WithBaseURL(server.URL), WithRetryPolicy(testRetryPolicy()))
+
+	start := time.Now()
+	if err := client.makeRequest(context.Background(), "GET", "/test", nil, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected Retry-After to be capped by MaxBackoff, took %v", elapsed)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("Expected 2 calls, got %d", got)
+	}
+
+	policy := &RetryPolicy{}
+	if got := policy.delay(1, header); got != maxRetryAfter {
+		t.Errorf("Expected Retry-After to be capped at %v without MaxBackoff, got %v", maxRetryAfter, got)
+	}
+}
+
+func TestRetryPolicyRetriesNetworkErrorsOnly(t *testing.T) {
+	_, badHost := http.NewRequest("GET", "http://bad host/test", nil)
+	req, err := http.NewRequest("GET", "ftp://example.com/test", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	_, badScheme := http.DefaultClient.Do(req)
+	_, nilURL := http.DefaultClient.Do(&http.Request{Method: "GET", Header: http.Header{}})
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"connection refused", &url.Error{Op: "Get", URL: "http://example.com", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}, true},
+		{"connection reset", &url.Error{Op: "Get", URL: "http://example.com", Err: syscall.ECONNRESET}, true},
+		{"timeout", &url.Error{Op: "Get", URL: "http://example.com", Err: timeoutError{}}, true},
+		{"closed connection", &url.Error{Op: "Get", URL: "http://example.com", Err: io.EOF}, true},
+		{"truncated body", fmt.Errorf("failed to read response body: %w", io.ErrUnexpectedEOF), true},
+		{"invalid host", fmt.Errorf("failed to create request: %w", badHost), false},
+		{"unsupported scheme", fmt.Errorf("request failed: %w", badScheme), false},
+		{"nil URL", fmt.Errorf("request failed: %w", nilURL), false},
+		{"rate limiter", fmt.Errorf("rate limiter: %w", errors.New("burst exceeded")), false},
+		{"cancelled", &url.Error{Op: "Get", URL: "http://example.com", Err: context.Canceled}, false},
+	}
+
+	policy := testRetryPolicy()
+	for _, tt := range tests {
+		if got := policy.shouldRetry(context.Background(), 0, tt.err); got != tt.want {
+			t.Errorf("%s: expected retry %t, got %t", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestRetryPolicyDoesNotRetryLocalErrors(t *testing.T) {
+	var calls int32
+	client := NewClient("test-api-key", WithBaseURL("http://example.com"), WithRetryPolicy(testRetryPolicy()),
+		WithMiddleware(func(next RequestFunc) RequestFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				atomic.AddInt32(&calls, 1)
+				return nil, errors.New("signing failed")
+			}
+		}))
+
+	if err := client.makeRequest(context.Background(), "GET", "/test", nil, nil); err == nil {
+		t.Error("Expected an error, got nil")
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected 1 call, got %d", got)
+	}
+}
+
+func TestRetryPolicyDoesNotRetryInvalidBaseURL(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient("test-api-key", WithBaseURL("http://bad host"), WithRetryPolicy(testRetryPolicy()), WithLogger(logger))
+
+	if err := client.makeRequest(context.Background(), "GET", "/test", nil, nil); err == nil {
+		t.Error("Expected an error, got nil")
+	}
+	if retries := strings.Count(logs.String(), "retrying"); retries != 0 {
+		t.Errorf("Expected exactly 1 attempt, got %d retries", retries)
+	}
+}
+
+// timeoutError is a network error that reports a timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{now.Add(5 * time.Second).Format(http.TimeFormat), 5 * time.Second, true},
+		{now.Add(-5 * time.Second).Format(http.TimeFormat), 0, true},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(http.Header{"Retry-After": []string{tt.value}}, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %v, %t; expected %v, %t", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := &RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+
+	for retry, base := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		5: time.Second,
+	} {
+		for i := 0; i < 20; i++ {
+			got := policy.delay(retry, http.Header{})
+			if got < base/2 || got > base*3/2 {
+				t.Errorf("delay(%d) = %v, expected within 50%% of %v", retry, got, base)
+			}
+		}
+	}
+}
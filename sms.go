@@ -0,0 +1,167 @@
+package esimgo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SMSEncoding represents the character encoding an SMS is sent with
+type SMSEncoding string
+
+// SMS encodings
+const (
+	SMSEncodingGSM7 SMSEncoding = "GSM-7"
+	SMSEncodingUCS2 SMSEncoding = "UCS-2"
+)
+
+const (
+	// MaxSMSSegments is the maximum number of segments accepted by SendSMS
+	MaxSMSSegments = 6
+	// maxAlphanumericSender is the maximum length of an alphanumeric sender ID
+	maxAlphanumericSender = 11
+	// maxNumericSender is the maximum length of a numeric sender (E.164)
+	maxNumericSender = 15
+)
+
+// gsm7Basic holds the characters of the GSM 03.38 default alphabet
+const gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// gsm7Extension holds the characters that need an escape septet
+const gsm7Extension = "\f^{}\\[~]|€"
+
+// SMSInfo describes how a message will be split when sent
+type SMSInfo struct {
+	Encoding SMSEncoding
+	// Units is the number of septets (GSM-7) or UTF-16 code units (UCS-2)
+	Units    int
+	Segments int
+}
+
+// AnalyzeSMS reports the encoding and number of segments needed to send a
+// message, following GSM 03.38 for GSM-7 and UTF-16 for UCS-2
+func AnalyzeSMS(message string) SMSInfo {
+	encoding := SMSEncodingGSM7
+	single, multi := 160, 153
+	costs := make([]int, 0, len(message))
+	for _, r := range message {
+		switch {
+		case strings.ContainsRune(gsm7Basic, r):
+			costs = append(costs, 1)
+		case strings.ContainsRune(gsm7Extension, r):
+			costs = append(costs, 2)
+		default:
+			encoding = SMSEncodingUCS2
+		}
+	}
+
+	if encoding == SMSEncodingUCS2 {
+		single, multi = 70, 67
+		costs = costs[:0]
+		for _, r := range message {
+			// Characters outside the BMP take a surrogate pair
+			cost := 1
+			if r > 0xFFFF {
+				cost = 2
+			}
+			costs = append(costs, cost)
+		}
+	}
+
+	units := 0
+	for _, cost := range costs {
+		units += cost
+	}
+	info := SMSInfo{Encoding: encoding, Units: units}
+	if units == 0 {
+		return info
+	}
+	if units <= single {
+		info.Segments = 1
+		return info
+	}
+
+	// Escape sequences and surrogate pairs are never split across segments
+	info.Segments = 1
+	used := 0
+	for _, cost := range costs {
+		if used+cost > multi {
+			info.Segments++
+			used = 0
+		}
+		used += cost
+	}
+	return info
+}
+
+// validateSMS checks a message and sender before they are sent
+func validateSMS(message, sender string) (SMSInfo, error) {
+	if strings.TrimSpace(message) == "" {
+		return SMSInfo{}, fmt.Errorf("%w: message is empty", ErrValidation)
+	}
+	info := AnalyzeSMS(message)
+	if info.Segments > MaxSMSSegments {
+		return info, fmt.Errorf("%w: message needs %d %s segments, at most %d are allowed",
+			ErrValidation, info.Segments, info.Encoding, MaxSMSSegments)
+	}
+
+	if sender == "" {
+		return info, nil
+	}
+	numeric := strings.TrimPrefix(sender, "+")
+	if numeric != "" && strings.Trim(numeric, "0123456789") == "" {
+		if len(numeric) > maxNumericSender {
+			return info, fmt.Errorf("%w: numeric sender must have at most %d digits", ErrValidation, maxNumericSender)
+		}
+		return info, nil
+	}
+	if len(sender) > maxAlphanumericSender {
+		return info, fmt.Errorf("%w: alphanumeric sender must have at most %d characters", ErrValidation, maxAlphanumericSender)
+	}
+	for _, r := range sender {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == ' ') {
+			return info, fmt.Errorf("%w: sender %q contains unsupported characters", ErrValidation, sender)
+		}
+	}
+	return info, nil
+}
+
+// SendSMSRequest represents a request to send an SMS to an eSIM
+type SendSMSRequest struct {
+	Message string `json:"message"`
+	From    string `json:"from,omitempty"`
+}
+
+// SendSMSResponse represents the response from sending an SMS
+type SendSMSResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	// Encoding and Segments describe how the message was split
+	Encoding SMSEncoding `json:"-"`
+	Segments int         `json:"-"`
+}
+
+// SendSMS sends an SMS to the device an eSIM is installed on. The message
+// and sender are validated before any request is made; errors.Is reports
+// ErrProfileNotInstalled when the eSIM profile is not installed.
+func (s *ESIMService) SendSMS(ctx context.Context, iccid ICCID, message string, sender string) (*SendSMSResponse, error) {
+	info, err := validateSMS(message, sender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send SMS: %w", err)
+	}
+
+	req := &SendSMSRequest{
+		Message: message,
+		From:    sender,
+	}
+
+	var resp SendSMSResponse
+	err = s.client.makeRequest(ctx, "POST", "/esims/"+iccid.PathSegment()+"/sms", req, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send SMS: %w", err)
+	}
+	resp.Encoding = info.Encoding
+	resp.Segments = info.Segments
+	return &resp, nil
+}
@@ -0,0 +1,119 @@
+package esimgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeSMS(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		encoding SMSEncoding
+		units    int
+		segments int
+	}{
+		{"empty", "", SMSEncodingGSM7, 0, 0},
+		{"plain GSM-7", "Your data is 80% used", SMSEncodingGSM7, 21, 1},
+		{"GSM-7 accents", "¡Hola! Quedan 200MB en España", SMSEncodingGSM7, 29, 1},
+		{"extension characters", "Top up for 5€ [now]", SMSEncodingGSM7, 22, 1},
+		{"160 septets", strings.Repeat("a", 160), SMSEncodingGSM7, 160, 1},
+		{"161 septets", strings.Repeat("a", 161), SMSEncodingGSM7, 161, 2},
+		{"306 septets", strings.Repeat("a", 306), SMSEncodingGSM7, 306, 2},
+		{"307 septets", strings.Repeat("a", 307), SMSEncodingGSM7, 307, 3},
+		// The escape septet of the last euro sign does not fit in the first segment
+		{"escape not split", strings.Repeat("a", 152) + "€" + strings.Repeat("a", 10), SMSEncodingGSM7, 164, 2},
+		{"UCS-2", "Ваш трафик израсходован на 80%", SMSEncodingUCS2, 30, 1},
+		{"70 UCS-2 units", strings.Repeat("ç", 70), SMSEncodingUCS2, 70, 1},
+		{"71 UCS-2 units", strings.Repeat("ç", 71), SMSEncodingUCS2, 71, 2},
+		{"emoji surrogate pairs", "Data 80% 📶", SMSEncodingUCS2, 11, 1},
+		{"surrogate pair not split", strings.Repeat("x", 66) + "📶" + strings.Repeat("x", 10), SMSEncodingUCS2, 78, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AnalyzeSMS(tt.message)
+			if got.Encoding != tt.encoding || got.Units != tt.units || got.Segments != tt.segments {
+				t.Errorf("AnalyzeSMS() = %+v, expected {%s %d %d}", got, tt.encoding, tt.units, tt.segments)
+			}
+		})
+	}
+}
+
+func TestSendSMS(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Method != "POST" {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+
+		switch r.URL.Path {
+		case "/esims/8944500102198304826/sms":
+			var req SendSMSRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("Failed to decode request: %v", err)
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if req.Message != "Your data is 80% used" || req.From != "eSIMGo" {
+				t.Errorf("Unexpected request %+v", req)
+			}
+			w.Write([]byte(`{"status":"Sent"}`))
+		case "/esims/8944500102198304834/sms":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"eSIM profile is not installed"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewESIMGoClient("test-api-key", WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	resp, err := client.ESIMs.SendSMS(ctx, "8944500102198304826", "Your data is 80% used", "eSIMGo")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Status != "Sent" || resp.Encoding != SMSEncodingGSM7 || resp.Segments != 1 {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+
+	_, err = client.ESIMs.SendSMS(ctx, "8944500102198304834", "Hello", "")
+	if !errors.Is(err, ErrProfileNotInstalled) {
+		t.Errorf("Expected ErrProfileNotInstalled, got %v", err)
+	}
+	if errors.Is(err, ErrValidation) {
+		t.Error("Expected a not installed profile not to be reported as a validation error")
+	}
+
+	invalid := []struct {
+		message string
+		sender  string
+	}{
+		{"   ", ""},
+		{strings.Repeat("a", 153*MaxSMSSegments+1), ""},
+		{"Hello", "TravelAlerts1"},
+		{"Hello", "eSIM-Go"},
+		{"Hello", "+1234567890123456"},
+	}
+	calls = 0
+	for _, tt := range invalid {
+		if _, err := client.ESIMs.SendSMS(ctx, "8944500102198304826", tt.message, tt.sender); !errors.Is(err, ErrValidation) {
+			t.Errorf("SendSMS(%q, %q): expected ErrValidation, got %v", tt.message, tt.sender, err)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("Expected invalid messages to be rejected before any request, got %d calls", calls)
+	}
+
+	if _, err := validateSMS("Hello", "+447700900123"); err != nil {
+		t.Errorf("Expected a numeric sender to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+package esimgo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidTransition is returned when a state change is impossible, such
+// as a bundle going from Expired back to Active
+var ErrInvalidTransition = errors.New("esimgo: invalid state transition")
+
+// BundleState represents the state of a bundle assignment. Values decoded
+// from JSON keep the string sent by the API, so unrecognised states are not
+// lost; the methods below compare states case-insensitively.
+type BundleState string
+
+// Bundle states
+const (
+	BundleStateProcessing BundleState = "Processing"
+	BundleStateQueued     BundleState = "Queued"
+	BundleStateActive     BundleState = "Active"
+	BundleStateDepleted   BundleState = "Depleted"
+	BundleStateExpired    BundleState = "Expired"
+	BundleStateRevoked    BundleState = "Revoked"
+	BundleStateLapsed     BundleState = "Lapsed"
+	// BundleStateUnknown stands for states this version does not know about
+	BundleStateUnknown BundleState = "Unknown"
+)
+
+var bundleStates = []BundleState{
+	BundleStateProcessing,
+	BundleStateQueued,
+	BundleStateActive,
+	BundleStateDepleted,
+	BundleStateExpired,
+	BundleStateRevoked,
+	BundleStateLapsed,
+}
+
+// bundleTransitions lists the states each bundle state can move to
+var bundleTransitions = map[BundleState][]BundleState{
+	BundleStateProcessing: {BundleStateQueued, BundleStateActive, BundleStateRevoked},
+	BundleStateQueued:     {BundleStateActive, BundleStateRevoked, BundleStateLapsed},
+	BundleStateActive:     {BundleStateDepleted, BundleStateExpired, BundleStateRevoked},
+}
+
+// ParseBundleState parses a bundle state case-insensitively, returning
+// BundleStateUnknown for unrecognised values
+func ParseBundleState(value string) BundleState {
+	for _, state := range bundleStates {
+		if strings.EqualFold(strings.TrimSpace(value), string(state)) {
+			return state
+		}
+	}
+	return BundleStateUnknown
+}
+
+// String implements fmt.Stringer
+func (s BundleState) String() string {
+	return string(s)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (s BundleState) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, keeping the raw value
+func (s *BundleState) UnmarshalText(text []byte) error {
+	*s = BundleState(text)
+	return nil
+}
+
+// IsKnown reports whether the state is one this version knows about
+func (s BundleState) IsKnown() bool {
+	return ParseBundleState(string(s)) != BundleStateUnknown
+}
+
+// IsPending reports whether the bundle has not started being consumed yet
+func (s BundleState) IsPending() bool {
+	state := ParseBundleState(string(s))
+	return state == BundleStateProcessing || state == BundleStateQueued
+}
+
+// IsActive reports whether the bundle is currently being consumed
+func (s BundleState) IsActive() bool {
+	return ParseBundleState(string(s)) == BundleStateActive
+}
+
+// IsTerminal reports whether the bundle can no longer change state
+func (s BundleState) IsTerminal() bool {
+	switch ParseBundleState(string(s)) {
+	case BundleStateDepleted, BundleStateExpired, BundleStateRevoked, BundleStateLapsed:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether a bundle can move from s to next.
+// Staying in the same state is always allowed, and transitions involving
+// unknown states cannot be judged so they are allowed too.
+func (s BundleState) CanTransitionTo(next BundleState) bool {
+	from, to := ParseBundleState(string(s)), ParseBundleState(string(next))
+	if from == to || from == BundleStateUnknown || to == BundleStateUnknown {
+		return true
+	}
+	for _, allowed := range bundleTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateBundleTransition returns an error wrapping ErrInvalidTransition
+// when a bundle cannot move from one state to the other
+func ValidateBundleTransition(from, to BundleState) error {
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("%w: bundle %s -> %s", ErrInvalidTransition, from, to)
+	}
+	return nil
+}
+
+// ProfileStatus represents the status of an eSIM profile. Like BundleState,
+// decoded values keep the string sent by the API.
+type ProfileStatus string
+
+// Profile statuses
+const (
+	ProfileStatusReleased    ProfileStatus = "Released"
+	ProfileStatusDownloaded  ProfileStatus = "Downloaded"
+	ProfileStatusInstalled   ProfileStatus = "Installed"
+	ProfileStatusEnabled     ProfileStatus = "Enabled"
+	ProfileStatusDisabled    ProfileStatus = "Disabled"
+	ProfileStatusDeleted     ProfileStatus = "Deleted"
+	ProfileStatusUnavailable ProfileStatus = "Unavailable"
+	// ProfileStatusUnknown stands for statuses this version does not know about
+	ProfileStatusUnknown ProfileStatus = "Unknown"
+)
+
+var profileStatuses = []ProfileStatus{
+	ProfileStatusReleased,
+	ProfileStatusDownloaded,
+	ProfileStatusInstalled,
+	ProfileStatusEnabled,
+	ProfileStatusDisabled,
+	ProfileStatusDeleted,
+	ProfileStatusUnavailable,
+}
+
+// profileTransitions lists the statuses each profile status can move to
+var profileTransitions = map[ProfileStatus][]ProfileStatus{
+	ProfileStatusReleased:   {ProfileStatusDownloaded, ProfileStatusInstalled, ProfileStatusUnavailable},
+	ProfileStatusDownloaded: {ProfileStatusInstalled, ProfileStatusDeleted, ProfileStatusUnavailable},
+	ProfileStatusInstalled:  {ProfileStatusEnabled, ProfileStatusDisabled, ProfileStatusDeleted},
+	ProfileStatusEnabled:    {ProfileStatusDisabled, ProfileStatusDeleted},
+	ProfileStatusDisabled:   {ProfileStatusEnabled, ProfileStatusDeleted},
+}
+
+// ParseProfileStatus parses a profile status case-insensitively, returning
+// ProfileStatusUnknown for unrecognised values
+func ParseProfileStatus(value string) ProfileStatus {
+	for _, status := range profileStatuses {
+		if strings.EqualFold(strings.TrimSpace(value), string(status)) {
+			return status
+		}
+	}
+	return ProfileStatusUnknown
+}
+
+// String implements fmt.Stringer
+func (s ProfileStatus) String() string {
+	return string(s)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (s ProfileStatus) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, keeping the raw value
+func (s *ProfileStatus) UnmarshalText(text []byte) error {
+	*s = ProfileStatus(text)
+	return nil
+}
+
+// IsKnown reports whether the status is one this version knows about
+func (s ProfileStatus) IsKnown() bool {
+	return ParseProfileStatus(string(s)) != ProfileStatusUnknown
+}
+
+// IsActive reports whether the profile is installed on a device
+func (s ProfileStatus) IsActive() bool {
+	status := ParseProfileStatus(string(s))
+	return status == ProfileStatusInstalled || status == ProfileStatusEnabled
+}
+
+// IsTerminal reports whether the profile can no longer be used
+func (s ProfileStatus) IsTerminal() bool {
+	status := ParseProfileStatus(string(s))
+	return status == ProfileStatusDeleted || status == ProfileStatusUnavailable
+}
+
+// CanTransitionTo reports whether a profile can move from s to next.
+// Staying in the same status is always allowed, and transitions involving
+// unknown statuses cannot be judged so they are allowed too.
+func (s ProfileStatus) CanTransitionTo(next ProfileStatus) bool {
+	from, to := ParseProfileStatus(string(s)), ParseProfileStatus(string(next))
+	if from == to || from == ProfileStatusUnknown || to == ProfileStatusUnknown {
+		return true
+	}
+	for _, allowed := range profileTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateProfileTransition returns an error wrapping ErrInvalidTransition
+// when a profile cannot move from one status to the other
+func ValidateProfileTransition(from, to ProfileStatus) error {
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("%w: profile %s -> %s", ErrInvalidTransition, from, to)
+	}
+	return nil
+}
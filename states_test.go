@@ -0,0 +1,159 @@
+package esimgo
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseStates(t *testing.T) {
+	bundleTests := []struct {
+		value string
+		want  BundleState
+	}{
+		{"Active", BundleStateActive},
+		{"queued", BundleStateQueued},
+		{" EXPIRED ", BundleStateExpired},
+		{"Suspended", BundleStateUnknown},
+		{"", BundleStateUnknown},
+	}
+	for _, tt := range bundleTests {
+		if got := ParseBundleState(tt.value); got != tt.want {
+			t.Errorf("ParseBundleState(%q): expected %s, got %s", tt.value, tt.want, got)
+		}
+	}
+
+	profileTests := []struct {
+		value string
+		want  ProfileStatus
+	}{
+		{"Installed", ProfileStatusInstalled},
+		{"released", ProfileStatusReleased},
+		{"Pending", ProfileStatusUnknown},
+	}
+	for _, tt := range profileTests {
+		if got := ParseProfileStatus(tt.value); got != tt.want {
+			t.Errorf("ParseProfileStatus(%q): expected %s, got %s", tt.value, tt.want, got)
+		}
+	}
+}
+
+func TestStatesJSON(t *testing.T) {
+	var esim ESIM
+	if err := json.Unmarshal([]byte(`{"iccid":"8944500102198304826","profileStatus":"installed"}`), &esim); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if esim.ProfileStatus != "installed" || !esim.ProfileStatus.IsActive() || !esim.ProfileStatus.IsKnown() {
+		t.Errorf("Expected the raw installed status, got %s", esim.ProfileStatus)
+	}
+
+	var assignment Assignment
+	if err := json.Unmarshal([]byte(`{"id":"a1","bundleState":"Paused"}`), &assignment); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if assignment.BundleState != "Paused" || assignment.BundleState.IsKnown() {
+		t.Errorf("Expected the raw unknown state Paused, got %s", assignment.BundleState)
+	}
+
+	var empty ESIM
+	if err := json.Unmarshal([]byte(`{"iccid":"8944500102198304826","profileStatus":""}`), &empty); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if empty.ProfileStatus != "" {
+		t.Errorf("Expected an empty status to stay empty, got %s", empty.ProfileStatus)
+	}
+
+	data, err := json.Marshal(struct {
+		State BundleState `json:"state"`
+	}{BundleStateDepleted})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(data) != `{"state":"Depleted"}` {
+		t.Errorf("Expected Depleted to be encoded as a string, got %s", data)
+	}
+}
+
+func TestStateClassification(t *testing.T) {
+	if !BundleStateQueued.IsPending() || BundleStateQueued.IsActive() || BundleStateQueued.IsTerminal() {
+		t.Error("Expected Queued to be pending only")
+	}
+	if !BundleStateActive.IsActive() || BundleStateActive.IsTerminal() {
+		t.Error("Expected Active to be active and not terminal")
+	}
+	for _, state := range []BundleState{BundleStateDepleted, BundleStateExpired, BundleStateRevoked, BundleStateLapsed} {
+		if !state.IsTerminal() {
+			t.Errorf("Expected %s to be terminal", state)
+		}
+	}
+	if BundleStateUnknown.IsTerminal() || BundleStateUnknown.IsKnown() {
+		t.Error("Expected Unknown to be neither terminal nor known")
+	}
+	if !BundleState("queued").IsPending() || !BundleState("ACTIVE").IsActive() || !BundleState("expired").IsTerminal() {
+		t.Error("Expected classification to ignore case")
+	}
+
+	if !ProfileStatusEnabled.IsActive() || ProfileStatusReleased.IsActive() {
+		t.Error("Expected only installed profiles to be active")
+	}
+	if !ProfileStatusDeleted.IsTerminal() || ProfileStatusDisabled.IsTerminal() {
+		t.Error("Expected Deleted to be terminal and Disabled not")
+	}
+	if !ProfileStatus("enabled").IsActive() || !ProfileStatus("deleted").IsTerminal() {
+		t.Error("Expected classification to ignore case")
+	}
+}
+
+func TestBundleTransitions(t *testing.T) {
+	tests := []struct {
+		from, to BundleState
+		valid    bool
+	}{
+		{BundleStateProcessing, BundleStateActive, true},
+		{BundleStateQueued, BundleStateActive, true},
+		{BundleStateQueued, BundleStateLapsed, true},
+		{BundleStateActive, BundleStateDepleted, true},
+		{BundleStateActive, BundleStateActive, true},
+		{BundleStateExpired, BundleStateActive, false},
+		{BundleStateDepleted, BundleStateQueued, false},
+		{BundleStateActive, BundleStateQueued, false},
+		{BundleStateQueued, BundleStateExpired, false},
+		{BundleStateUnknown, BundleStateActive, true},
+		{"active", BundleStateDepleted, true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateBundleTransition(tt.from, tt.to)
+		if tt.valid && err != nil {
+			t.Errorf("%s -> %s: expected valid, got %v", tt.from, tt.to, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidTransition) {
+			t.Errorf("%s -> %s: expected ErrInvalidTransition, got %v", tt.from, tt.to, err)
+		}
+	}
+}
+
+func TestProfileTransitions(t *testing.T) {
+	tests := []struct {
+		from, to ProfileStatus
+		valid    bool
+	}{
+		{ProfileStatusReleased, ProfileStatusDownloaded, true},
+		{ProfileStatusReleased, ProfileStatusInstalled, true},
+		{ProfileStatusInstalled, ProfileStatusDisabled, true},
+		{ProfileStatusDisabled, ProfileStatusEnabled, true},
+		{ProfileStatusDeleted, ProfileStatusInstalled, false},
+		{ProfileStatusEnabled, ProfileStatusReleased, false},
+		{ProfileStatusReleased, ProfileStatusEnabled, false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateProfileTransition(tt.from, tt.to)
+		if tt.valid && err != nil {
+			t.Errorf("%s -> %s: expected valid, got %v", tt.from, tt.to, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidTransition) {
+			t.Errorf("%s -> %s: expected ErrInvalidTransition, got %v", tt.from, tt.to, err)
+		}
+	}
+}
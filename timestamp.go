@@ -0,0 +1,90 @@
+package esimgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timestamp is a time decoded from the several representations used by the
+// API: RFC 3339 strings, "2006-01-02 15:04:05" strings and Unix timestamps
+// in seconds or milliseconds
+type Timestamp struct {
+	time.Time
+}
+
+// timestampLayouts lists the string layouts accepted by Timestamp
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05 -0700 MST",
+	"2006-01-02",
+}
+
+// ParseTimestamp parses a time in any of the representations used by the API
+func ParseTimestamp(value string) (Timestamp, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Timestamp{}, nil
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return unixTimestamp(n), nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return Timestamp{t}, nil
+		}
+	}
+	return Timestamp{}, fmt.Errorf("unsupported timestamp %q", value)
+}
+
+// unixTimestamp interprets n as milliseconds when it is too large to be a
+// plausible number of seconds
+func unixTimestamp(n int64) Timestamp {
+	if n > 1e11 || n < -1e11 {
+		return Timestamp{time.UnixMilli(n).UTC()}
+	}
+	return Timestamp{time.Unix(n, 0).UTC()}
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*t = Timestamp{}
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		parsed, err := ParseTimestamp(value)
+		if err != nil {
+			return err
+		}
+		*t = parsed
+		return nil
+	}
+
+	n, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("unsupported timestamp %s", data)
+	}
+	*t = unixTimestamp(int64(n))
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding zero times as null
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Time.Format(time.RFC3339Nano))
+}
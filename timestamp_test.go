@@ -0,0 +1,56 @@
+package esimgo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestampUnmarshal(t *testing.T) {
+	want := time.Date(2024, 3, 1, 10, 15, 0, 0, time.UTC)
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{`"2024-03-01T10:15:00Z"`, want},
+		{`"2024-03-01T11:15:00+01:00"`, want},
+		{`"2024-03-01T10:15:00"`, want},
+		{`"2024-03-01 10:15:00"`, want},
+		{`"2024-03-01 10:15:00.000"`, want},
+		{`1709288100`, want},
+		{`1709288100000`, want},
+		{`"1709288100"`, want},
+		{`null`, time.Time{}},
+		{`""`, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		var got Timestamp
+		if err := json.Unmarshal([]byte(tt.input), &got); err != nil {
+			t.Errorf("Unmarshal(%s): unexpected error %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Unmarshal(%s) = %v, expected %v", tt.input, got.Time, tt.want)
+		}
+	}
+
+	var invalid Timestamp
+	if err := json.Unmarshal([]byte(`"yesterday"`), &invalid); err == nil {
+		t.Error("Expected an error for an unsupported timestamp")
+	}
+}
+
+func TestTimestampMarshal(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Set   Timestamp `json:"set"`
+		Unset Timestamp `json:"unset"`
+	}{Set: Timestamp{time.Date(2024, 3, 1, 10, 15, 0, 0, time.UTC)}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := `{"set":"2024-03-01T10:15:00Z","unset":null}`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}